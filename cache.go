@@ -0,0 +1,43 @@
+package raiderio
+
+import "sync"
+
+// ETagCache stores api responses by request url, along with the ETag the
+// api sent for them, for conditional requests, see WithETagCache
+// Implementations must be safe for concurrent use
+type ETagCache interface {
+	// Get returns the ETag and body stored for a request url
+	Get(url string) (etag string, body []byte, ok bool)
+	// Set stores the ETag and body of a response for a request url
+	Set(url string, etag string, body []byte)
+}
+
+// memoryETagCache is an ETagCache which keeps every response in memory
+type memoryETagCache struct {
+	mu      sync.RWMutex
+	entries map[string]etagEntry
+}
+
+type etagEntry struct {
+	etag string
+	body []byte
+}
+
+// NewMemoryETagCache returns an ETagCache which keeps responses in memory
+// Entries are never evicted, so it suits polling a fixed set of resources
+func NewMemoryETagCache() ETagCache {
+	return &memoryETagCache{entries: map[string]etagEntry{}}
+}
+
+func (m *memoryETagCache) Get(url string) (string, []byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.entries[url]
+	return e.etag, e.body, ok
+}
+
+func (m *memoryETagCache) Set(url string, etag string, body []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[url] = etagEntry{etag: etag, body: body}
+}
@@ -1,5 +1,12 @@
 package raiderio
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // CharacterQuery is a struct that represents the query parameters
 // sent for a character profile request
 type CharacterQuery struct {
@@ -8,30 +15,245 @@ type CharacterQuery struct {
 	Name          string
 	TalentLoadout bool
 	Gear          bool
-	fields        []string
+	Guild         bool
+
+	MythicPlusWeeklyHighestRuns         bool
+	MythicPlusPreviousWeeklyHighestRuns bool
+	MythicPlusScores                    bool
+
+	// Season requests the mythic plus scores of a season by slug, or the
+	// "current" and "previous" aliases, instead of the current season
+	// Setting it implies MythicPlusScores, unknown seasons fail with
+	// ErrInvalidSeason before the request is sent
+	Season string
+
+	// RawFields are appended to the requested fields as is, in order,
+	// after the fields requested by the options above. Use it for fields
+	// the library does not model, including colon scoped fields,
+	// ex: "mythic_plus_scores_by_season:season-tww-2"
+	// raid_progression cannot be scoped by expansion, see ErrScopedRaidProgression
+	RawFields []string
+	fields    []string
 }
 
 // Character is a struct that represents the response from
 // a character profile request
+// ActiveSpec and ActiveRole are the spec and role the character is
+// currently playing, as of the last crawl. The character's mythic plus
+// scores may cover other specs, see MythicPlusScores.BySpec
+// In boss kill and mythic plus run rosters, the spec (Spec for boss kills,
+// ActiveSpec for runs) is the spec played in that kill or run
 type Character struct {
-	Name              string        `json:"name"`
-	Race              string        `json:"race"`
-	Class             string        `json:"class"`
-	ActiveSpec        string        `json:"active_spec_name"`
-	ActiveRole        string        `json:"active_spec_role"`
-	Gender            string        `json:"gender"`
-	Faction           string        `json:"faction"`
-	Spec              string        `json:"spec"`
-	AchievementPoints int64         `json:"achievement_points"`
-	HonorableKills    int64         `json:"honorable_kills"`
-	ThumbnailUrl      string        `json:"thumbnail_url"`
-	Region            string        `json:"region"`
-	Realm             string        `json:"realm"`
-	LastCrawledAt     string        `json:"last_crawled_at"`
-	ProfileUrl        string        `json:"profile_url"`
-	ProfileBanner     string        `json:"profile_banner"`
-	TalentLoadout     TalentLoadout `json:"talentLoadout"`
-	Gear              Gear          `json:"gear"`
+	ID                int            `json:"id"`
+	Name              string         `json:"name"`
+	Race              string         `json:"race"`
+	Class             string         `json:"class"`
+	Level             int            `json:"level"`
+	ActiveSpec        string         `json:"active_spec_name"`
+	ActiveRole        string         `json:"active_spec_role"`
+	Gender            string         `json:"gender"`
+	Faction           string         `json:"faction"`
+	Spec              string         `json:"spec"`
+	AchievementPoints int64          `json:"achievement_points"`
+	HonorableKills    int64          `json:"honorable_kills"`
+	ThumbnailUrl      string         `json:"thumbnail_url"`
+	Region            string         `json:"region"`
+	Realm             string         `json:"realm"`
+	LastCrawledAt     string         `json:"last_crawled_at"`
+	ProfileUrl        string         `json:"profile_url"`
+	ProfileBanner     string         `json:"profile_banner"`
+	TalentLoadout     TalentLoadout  `json:"talentLoadout"`
+	Gear              Gear           `json:"gear"`
+	Guild             CharacterGuild `json:"guild"`
+
+	MythicPlusWeeklyHighestRuns         []MythicPlusRun          `json:"mythic_plus_weekly_highest_level_runs"`
+	MythicPlusPreviousWeeklyHighestRuns []MythicPlusRun          `json:"mythic_plus_previous_weekly_highest_level_runs"`
+	MythicPlusScoresBySeason            []MythicPlusSeasonScores `json:"mythic_plus_scores_by_season"`
+
+	// RaidAchievementCurve is requested with a colon scoped raw field
+	// listing raid slugs, ex: "raid_achievement_curve:nerubar-palace"
+	RaidAchievementCurve []RaidAchievementCurve `json:"raid_achievement_curve"`
+
+	// Warnings lists the requested fields which were missing from the
+	// response, ex: a field not supported this expansion. Requested fields
+	// which are present but empty are not reported. The weekly runs fields
+	// are also missing for characters with no runs that week
+	Warnings []string `json:"-"`
+}
+
+// RaidAchievementCurve is a struct that represents whether a character
+// earned Ahead of the Curve and Cutting Edge for a raid. Dates are RFC3339
+// strings as returned by the api, empty when the achievement is not earned
+type RaidAchievementCurve struct {
+	Raid        string `json:"raid"`
+	AOTC        string `json:"aotc"`
+	CuttingEdge string `json:"cutting_edge"`
+}
+
+// Requirements is a struct that represents the thresholds a character must
+// meet, ex: to apply to a guild, see MeetsRequirements. Zero values are
+// not checked
+type Requirements struct {
+	MinItemLevel       int
+	MinMythicPlusScore float64
+
+	// AheadOfTheCurve and CuttingEdge list raid slugs the achievement is
+	// required for. Cutting Edge also meets Ahead of the Curve
+	AheadOfTheCurve []string
+	CuttingEdge     []string
+}
+
+// MeetsRequirements checks the character against req, returning whether
+// every requirement is met and a description of each unmet requirement
+// Requirements on data the character was not requested with are unmet and
+// described as unknown, ex: a minimum item level without Gear
+// The mythic plus score is the first season of MythicPlusScoresBySeason,
+// the current season when requested with MythicPlusScores. The raid
+// achievements require a raid_achievement_curve raw field for the raids
+func (c *Character) MeetsRequirements(req Requirements) (bool, []string) {
+	var unmet []string
+	if req.MinItemLevel > 0 {
+		switch ilvl := c.Gear.ItemLevelEquipped; {
+		case ilvl == 0:
+			unmet = append(unmet, "item level unknown, request Gear")
+		case ilvl < req.MinItemLevel:
+			unmet = append(unmet, fmt.Sprintf("item level %d below %d", ilvl, req.MinItemLevel))
+		}
+	}
+
+	if req.MinMythicPlusScore > 0 {
+		switch {
+		case len(c.MythicPlusScoresBySeason) == 0:
+			unmet = append(unmet, "mythic plus score unknown, request MythicPlusScores")
+		case c.MythicPlusScoresBySeason[0].Scores.All < req.MinMythicPlusScore:
+			unmet = append(unmet, fmt.Sprintf("mythic plus score %.1f below %.1f",
+				c.MythicPlusScoresBySeason[0].Scores.All, req.MinMythicPlusScore))
+		}
+	}
+
+	curves := map[string]RaidAchievementCurve{}
+	for _, rc := range c.RaidAchievementCurve {
+		curves[rc.Raid] = rc
+	}
+
+	for _, raid := range req.AheadOfTheCurve {
+		rc, ok := curves[raid]
+		switch {
+		case !ok:
+			unmet = append(unmet, "ahead of the curve unknown for "+raid+", request raid_achievement_curve:"+raid)
+		case rc.AOTC == "" && rc.CuttingEdge == "":
+			unmet = append(unmet, "ahead of the curve missing for "+raid)
+		}
+	}
+
+	for _, raid := range req.CuttingEdge {
+		rc, ok := curves[raid]
+		switch {
+		case !ok:
+			unmet = append(unmet, "cutting edge unknown for "+raid+", request raid_achievement_curve:"+raid)
+		case rc.CuttingEdge == "":
+			unmet = append(unmet, "cutting edge missing for "+raid)
+		}
+	}
+
+	return len(unmet) == 0, unmet
+}
+
+// CharacterGuild is a struct that represents the current guild of a
+// character in a character profile response
+// The api only reports the guild at the time of the last crawl, it does
+// not provide a history of previous guilds
+type CharacterGuild struct {
+	Name  string `json:"name"`
+	Realm string `json:"realm"`
+}
+
+// SameAs reports whether the character has the given id
+// The id is stable across name changes and realm transfers, so a stored id
+// that no longer matches means the name now belongs to another character
+// Always false when the api did not report an id
+func (c *Character) SameAs(id int) bool {
+	return c.ID != 0 && c.ID == id
+}
+
+// Key returns a canonical identity for the character, for deduplicating
+// characters from different sources
+// The format is "region/realm/name", with the region slug lowercased, the
+// realm normalized to its slug, see NormalizeRealm, and the name lowercased,
+// ex: "us/argent-dawn/highervalue". The format is stable across versions
+func (c *Character) Key() string {
+	return characterKey(c.Region, c.Realm, c.Name)
+}
+
+// Key returns the canonical identity of the queried character, in the same
+// format as Character.Key, so queries and results can be matched
+func (cq *CharacterQuery) Key() string {
+	region := ""
+	if cq.Region != nil {
+		region = cq.Region.Slug
+	}
+	return characterKey(region, cq.Realm, cq.Name)
+}
+
+// characterKey builds the key returned by Character.Key
+func characterKey(region, realm, name string) string {
+	region = strings.ToLower(strings.TrimSpace(region))
+	realm = NormalizeRealm(RegionBySlug(region), realm)
+	name = strings.ToLower(strings.TrimSpace(name))
+	return region + "/" + realm + "/" + name
+}
+
+// RealmName returns the display name of the character's realm
+// Falls back to a title cased realm slug when the display name is unknown
+func (c *Character) RealmName() string {
+	return realmDisplayName(c.Realm)
+}
+
+// Flatten returns the character as a single level map, suitable for
+// exporting as a row to CSV or similar formats
+// Keys: name, region, realm, class, spec, role, race, faction, guild,
+// achievement_points, item_level, mplus_score, profile_url
+// mplus_score is the first season of MythicPlusScoresBySeason, omitted
+// when the scores were not requested
+// Keys for empty or zero values, ex: fields not requested, are omitted
+func (c *Character) Flatten() map[string]interface{} {
+	spec := c.ActiveSpec
+	if spec == "" {
+		spec = c.Spec
+	}
+
+	row := map[string]interface{}{}
+	strs := map[string]string{
+		"name":        c.Name,
+		"region":      c.Region,
+		"realm":       c.Realm,
+		"class":       c.Class,
+		"spec":        spec,
+		"role":        c.ActiveRole,
+		"race":        c.Race,
+		"faction":     c.Faction,
+		"guild":       c.Guild.Name,
+		"profile_url": c.ProfileUrl,
+	}
+	for k, v := range strs {
+		if v != "" {
+			row[k] = v
+		}
+	}
+
+	if c.AchievementPoints != 0 {
+		row["achievement_points"] = c.AchievementPoints
+	}
+
+	if c.Gear.ItemLevelEquipped != 0 {
+		row["item_level"] = c.Gear.ItemLevelEquipped
+	}
+
+	if len(c.MythicPlusScoresBySeason) > 0 {
+		row["mplus_score"] = c.MythicPlusScoresBySeason[0].Scores.All
+	}
+
+	return row
 }
 
 // Gear is a struct that represents the gear of a character
@@ -74,10 +296,95 @@ type Item struct {
 	Name        string `json:"name"`
 	ItemQuality int    `json:"item_quality"`
 	IsLegendary bool   `json:"is_legendary"`
+	Enchant     int    `json:"enchant"`
 	Gems        []int  `json:"gems"`
 	Bonuses     []int  `json:"bonuses"`
 }
 
+// EnchantableSlots lists the gear slots GearIssues expects to be enchanted
+// The slots are for The War Within, and must be updated when the
+// enchantable slots change in a new expansion
+var EnchantableSlots = []string{"back", "chest", "wrist", "legs", "feet", "finger1", "finger2", "mainhand"}
+
+// TierSlots lists the gear slots tier set items are equipped in
+var TierSlots = []string{"head", "shoulder", "chest", "hands", "legs"}
+
+// TierSetItemIDs is the set of item ids TierSetCount counts as tier set
+// pieces. The ids differ per class and per raid tier, and the library does
+// not ship them, so it must be filled in, and updated each tier, with the
+// ids of the current tier's items, ex: TierSetItemIDs[229271] = true
+var TierSetItemIDs = map[int]bool{}
+
+// TierSetCount returns the number of tier set pieces the character has
+// equipped, per TierSetItemIDs, ex: 4 for the four piece set bonus
+// Returns 0 when the character was not requested with Gear
+func (c *Character) TierSetCount() int {
+	count := 0
+	items := c.Gear.Items.bySlot()
+	for _, slot := range TierSlots {
+		if id := items[slot].ID; id != 0 && TierSetItemIDs[id] {
+			count++
+		}
+	}
+	return count
+}
+
+// GearIssue is a struct that represents a problem with an equipped item
+type GearIssue struct {
+	Slot    string
+	Problem string
+}
+
+// Problems GearIssues reports for an item
+const (
+	GearIssueMissingEnchant string = "missing enchant"
+	GearIssueEmptySocket    string = "empty gem socket"
+)
+
+// bySlot returns the equipped items keyed by the slot names used by the api
+func (i *Items) bySlot() map[string]Item {
+	return map[string]Item{
+		"head": i.Head, "neck": i.Neck, "shoulder": i.Shoulder, "back": i.Back,
+		"chest": i.Chest, "wrist": i.Wrist, "hands": i.Hands, "waist": i.Waist,
+		"legs": i.Legs, "feet": i.Feet, "finger1": i.Finger1, "finger2": i.Finger2,
+		"trinket1": i.Trinket1, "trinket2": i.Trinket2, "mainhand": i.Mainhand,
+		"offhand": i.Offhand, "shirt": i.Shirt, "tabard": i.Tabard,
+	}
+}
+
+// GearIssues audits the character's equipped items, reporting enchantable
+// slots (see EnchantableSlots) with no enchant, and sockets with no gem
+// The api reports an empty socket as a gem id of 0
+// Empty slots are skipped. Returns an empty slice when no issues are found
+// Requires the character to be requested with Gear
+func (c *Character) GearIssues() []GearIssue {
+	issues := []GearIssue{}
+	items := c.Gear.Items.bySlot()
+
+	for _, slot := range EnchantableSlots {
+		item := items[slot]
+		if item.ID != 0 && item.Enchant == 0 {
+			issues = append(issues, GearIssue{Slot: slot, Problem: GearIssueMissingEnchant})
+		}
+	}
+
+	slots := make([]string, 0, len(items))
+	for slot := range items {
+		slots = append(slots, slot)
+	}
+	sort.Strings(slots)
+
+	for _, slot := range slots {
+		for _, gem := range items[slot].Gems {
+			if gem == 0 {
+				issues = append(issues, GearIssue{Slot: slot, Problem: GearIssueEmptySocket})
+			}
+		}
+	}
+
+	return issues
+}
+
 // TalentLoadout is a struct of a talent loadout
 // It includes the spec id and talent loadout string
 type TalentLoadout struct {
@@ -85,22 +392,109 @@ type TalentLoadout struct {
 	LoadoutText   string `json:"loadout_text"`
 }
 
-// validateCharacterQuery creates and validates a CharacterQuery struct
-// It returns an error if any of the required parameters are empty
-// or if the fields are invalid
-func validateCharacterQuery(cq *CharacterQuery) error {
+// Merge adds the fields requested by other to the query, so a single
+// request fetches the fields of both. Raw fields are appended, skipping
+// fields already requested
+// A region, realm or name missing from the query is taken from other
+// Returns ErrQueryConflict, leaving the query unchanged, if the queries
+// are for different characters
+func (cq *CharacterQuery) Merge(other *CharacterQuery) error {
+	if other == nil {
+		return ErrNilQuery
+	}
+
+	if cq.Region != nil && other.Region != nil && cq.Region.Slug != other.Region.Slug ||
+		cq.Realm != "" && other.Realm != "" && !strings.EqualFold(cq.Realm, other.Realm) ||
+		cq.Name != "" && other.Name != "" && !strings.EqualFold(cq.Name, other.Name) ||
+		cq.Season != "" && other.Season != "" && cq.Season != other.Season {
+		return ErrQueryConflict
+	}
+
 	if cq.Region == nil {
-		return ErrInvalidRegion
+		cq.Region = other.Region
 	}
 
 	if cq.Realm == "" {
-		return ErrInvalidRealm
+		cq.Realm = other.Realm
+	}
+
+	if cq.Name == "" {
+		cq.Name = other.Name
+	}
+
+	if cq.Season == "" {
+		cq.Season = other.Season
+	}
+
+	cq.TalentLoadout = cq.TalentLoadout || other.TalentLoadout
+	cq.Gear = cq.Gear || other.Gear
+	cq.Guild = cq.Guild || other.Guild
+	cq.MythicPlusWeeklyHighestRuns = cq.MythicPlusWeeklyHighestRuns || other.MythicPlusWeeklyHighestRuns
+	cq.MythicPlusPreviousWeeklyHighestRuns = cq.MythicPlusPreviousWeeklyHighestRuns || other.MythicPlusPreviousWeeklyHighestRuns
+	cq.MythicPlusScores = cq.MythicPlusScores || other.MythicPlusScores
+
+	seen := map[string]bool{}
+	var raw []string
+	for _, f := range append(append([]string(nil), cq.RawFields...), other.RawFields...) {
+		f = strings.TrimSpace(f)
+		if f != "" && !seen[f] {
+			seen[f] = true
+			raw = append(raw, f)
+		}
+	}
+	cq.RawFields = raw
+
+	return nil
+}
+
+// responseKeys maps requested fields to the response keys they populate,
+// for fields where the two differ
+var responseKeys = map[string]string{
+	"talents": "talentLoadout",
+}
+
+// missingFieldWarnings returns a warning for each requested field whose key
+// is missing from the top level of the response body
+// Colon scoped fields are checked by the field name before the colon
+func missingFieldWarnings(body []byte, fields []string) []string {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil
+	}
+
+	var warnings []string
+	for _, f := range fields {
+		key := strings.SplitN(f, ":", 2)[0]
+		if k, ok := responseKeys[key]; ok {
+			key = k
+		}
+
+		if _, ok := keys[key]; !ok {
+			warnings = append(warnings, "requested field missing from response: "+f)
+		}
+	}
+	return warnings
+}
+
+// validate validates the CharacterQuery and builds its requested fields
+// It returns an error if any of the required parameters are empty
+// or if the fields are invalid
+func (cq *CharacterQuery) validate() error {
+	err := validateRegionRealm(cq.Region, cq.Realm)
+	if err != nil {
+		return err
 	}
 
 	if cq.Name == "" {
 		return ErrInvalidCharName
 	}
 
+	if cq.Season != "" && !seasonValid(cq.Season) {
+		return ErrInvalidSeason
+	}
+
+	// rebuilt on every validation, so a reused query doesn't repeat fields
+	cq.fields = nil
 	if cq.TalentLoadout {
 		cq.fields = append(cq.fields, "talents")
 	}
@@ -109,5 +503,28 @@ func validateCharacterQuery(cq *CharacterQuery) error {
 		cq.fields = append(cq.fields, "gear")
 	}
 
+	if cq.Guild {
+		cq.fields = append(cq.fields, "guild")
+	}
+
+	if cq.MythicPlusWeeklyHighestRuns {
+		cq.fields = append(cq.fields, "mythic_plus_weekly_highest_level_runs")
+	}
+
+	if cq.MythicPlusPreviousWeeklyHighestRuns {
+		cq.fields = append(cq.fields, "mythic_plus_previous_weekly_highest_level_runs")
+	}
+
+	if cq.Season != "" {
+		cq.fields = append(cq.fields, "mythic_plus_scores_by_season:"+cq.Season)
+	} else if cq.MythicPlusScores {
+		cq.fields = append(cq.fields, "mythic_plus_scores_by_season:current")
+	}
+
+	if err := validateRawFields(cq.RawFields); err != nil {
+		return err
+	}
+
+	cq.fields = appendRawFields(cq.fields, cq.RawFields)
 	return nil
 }
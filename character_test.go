@@ -0,0 +1,702 @@
+package raiderio_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestCharacterRealmName(t *testing.T) {
+	testCases := []struct {
+		realm        string
+		expectedName string
+	}{
+		{realm: "argent-dawn", expectedName: "Argent Dawn"},
+		{realm: "illidan", expectedName: "Illidan"},
+		{realm: "Illidan", expectedName: "Illidan"},
+		{realm: "Azjol-Nerub", expectedName: "Azjol-Nerub"},
+		{realm: "", expectedName: ""},
+	}
+
+	for _, tc := range testCases {
+		char := raiderio.Character{Realm: tc.realm}
+		if char.RealmName() != tc.expectedName {
+			t.Fatalf("realm name expected: %v, got: %v", tc.expectedName, char.RealmName())
+		}
+	}
+}
+
+func TestCharacterSameAs(t *testing.T) {
+	testCases := []struct {
+		char     raiderio.Character
+		id       int
+		expected bool
+	}{
+		{char: raiderio.Character{ID: 12345, Name: "Thrall"}, id: 12345, expected: true},
+		{char: raiderio.Character{ID: 67890, Name: "Thrall"}, id: 12345, expected: false},
+		{char: raiderio.Character{Name: "Thrall"}, id: 0, expected: false},
+	}
+
+	for _, tc := range testCases {
+		if tc.char.SameAs(tc.id) != tc.expected {
+			t.Fatalf("character %v same as %v expected: %v, got: %v", tc.char.ID, tc.id, tc.expected, !tc.expected)
+		}
+	}
+}
+
+func TestGuessRole(t *testing.T) {
+	testCases := []struct {
+		char         raiderio.Character
+		expectedRole raiderio.Role
+	}{
+		{char: raiderio.Character{Class: "Mage", ActiveSpec: "Fire", ActiveRole: "DPS"}, expectedRole: raiderio.Roles.DPS},
+		{char: raiderio.Character{Class: "Priest", ActiveSpec: "Holy", ActiveRole: "HEALING"}, expectedRole: raiderio.Roles.Healer},
+		{char: raiderio.Character{Class: "Death Knight", ActiveSpec: "Blood"}, expectedRole: raiderio.Roles.Tank},
+		{char: raiderio.Character{Class: "demon-hunter", Spec: "vengeance"}, expectedRole: raiderio.Roles.Tank},
+		{char: raiderio.Character{Class: "evoker", Spec: "preservation"}, expectedRole: raiderio.Roles.Healer},
+		{char: raiderio.Character{Class: "mage", Spec: "unknown-spec"}, expectedRole: raiderio.Roles.Unknown},
+		{char: raiderio.Character{}, expectedRole: raiderio.Roles.Unknown},
+	}
+
+	for _, tc := range testCases {
+		role := tc.char.GuessRole()
+		if role != tc.expectedRole {
+			t.Fatalf("%v %v expected role: %v, got: %v", tc.char.Class, tc.char.Spec, tc.expectedRole, role)
+		}
+	}
+
+	if raiderio.Roles.DPS.String() != "DPS" || raiderio.Role("").String() != "Unknown" {
+		t.Fatalf("unexpected role display names")
+	}
+}
+
+func TestGearIssues(t *testing.T) {
+	enchanted := func(id int) raiderio.Item {
+		return raiderio.Item{ID: id, Enchant: 7000}
+	}
+	fullyEnchanted := raiderio.Items{
+		Back: enchanted(1), Chest: enchanted(2), Wrist: enchanted(3), Legs: enchanted(4),
+		Feet: enchanted(5), Finger1: enchanted(6), Finger2: enchanted(7), Mainhand: enchanted(8),
+		Neck: raiderio.Item{ID: 9, Gems: []int{213743, 213746}},
+	}
+
+	missing := fullyEnchanted
+	missing.Wrist.Enchant = 0
+	missing.Neck.Gems = []int{213743, 0}
+	missing.Offhand = raiderio.Item{}
+
+	testCases := []struct {
+		items          raiderio.Items
+		expectedIssues []raiderio.GearIssue
+	}{
+		{items: fullyEnchanted, expectedIssues: []raiderio.GearIssue{}},
+		{items: missing, expectedIssues: []raiderio.GearIssue{
+			{Slot: "wrist", Problem: raiderio.GearIssueMissingEnchant},
+			{Slot: "neck", Problem: raiderio.GearIssueEmptySocket},
+		}},
+	}
+
+	for _, tc := range testCases {
+		char := raiderio.Character{Gear: raiderio.Gear{Items: tc.items}}
+		issues := char.GearIssues()
+		if issues == nil || len(issues) != len(tc.expectedIssues) {
+			t.Fatalf("expected issues: %v, got: %v", tc.expectedIssues, issues)
+		}
+
+		for i := range issues {
+			if issues[i] != tc.expectedIssues[i] {
+				t.Fatalf("expected issues: %v, got: %v", tc.expectedIssues, issues)
+			}
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	char := raiderio.Character{
+		Name:       "Highervalue",
+		Region:     "us",
+		Realm:      "Illidan",
+		Class:      "Mage",
+		ActiveSpec: "Fire",
+		Gear:       raiderio.Gear{ItemLevelEquipped: 639},
+	}
+	withScores := char
+	withScores.MythicPlusScoresBySeason = []raiderio.MythicPlusSeasonScores{
+		{Season: "season-tww-3", Scores: raiderio.MythicPlusScores{All: 3150.5}},
+		{Season: "season-tww-2", Scores: raiderio.MythicPlusScores{All: 2800}},
+	}
+
+	expected := map[string]interface{}{
+		"name":       "Highervalue",
+		"region":     "us",
+		"realm":      "Illidan",
+		"class":      "Mage",
+		"spec":       "Fire",
+		"item_level": 639,
+	}
+	expectedWithScores := map[string]interface{}{"mplus_score": 3150.5}
+	for k, v := range expected {
+		expectedWithScores[k] = v
+	}
+
+	testCases := []struct {
+		char     raiderio.Character
+		expected map[string]interface{}
+	}{
+		{char: char, expected: expected},
+		{char: withScores, expected: expectedWithScores},
+	}
+
+	for _, tc := range testCases {
+		row := tc.char.Flatten()
+		if len(row) != len(tc.expected) {
+			t.Fatalf("expected row: %v, got: %v", tc.expected, row)
+		}
+
+		for k, v := range tc.expected {
+			if row[k] != v {
+				t.Fatalf("expected %v: %v, got: %v", k, v, row[k])
+			}
+		}
+	}
+}
+
+func TestGetCharacterRawFields(t *testing.T) {
+	var fields []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = append(fields, r.URL.Query().Get("fields"))
+		w.Write([]byte(`{"name":"Highervalue"}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	cq := &raiderio.CharacterQuery{
+		Region:    raiderio.Regions.US,
+		Realm:     "illidan",
+		Name:      "highervalue",
+		Gear:      true,
+		RawFields: []string{"mythic_plus_scores_by_season:season-tww-2", "", "mythic_plus_scores_by_season:season-tww-1"},
+	}
+
+	// the same query is sent twice, to check fields are not repeated
+	for i := 0; i < 2; i++ {
+		_, err := client.GetCharacter(defaultCtx, cq)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := "gear,mythic_plus_scores_by_season:season-tww-2,mythic_plus_scores_by_season:season-tww-1"
+	for _, f := range fields {
+		if f != expected {
+			t.Fatalf("expected fields: %v, got: %v", expected, f)
+		}
+	}
+}
+
+func TestGetCharacterSeason(t *testing.T) {
+	var fields []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = append(fields, r.URL.Query().Get("fields"))
+		w.Write([]byte(`{"name":"Highervalue"}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	testCases := []struct {
+		season         string
+		scores         bool
+		expectedFields string
+		expectedErr    error
+	}{
+		{scores: true, expectedFields: "mythic_plus_scores_by_season:current"},
+		{season: "season-tww-2", expectedFields: "mythic_plus_scores_by_season:season-tww-2"},
+		{season: "season-tww-1", scores: true, expectedFields: "mythic_plus_scores_by_season:season-tww-1"},
+		{season: "season-tww-9", expectedErr: raiderio.ErrInvalidSeason},
+	}
+
+	for _, tc := range testCases {
+		fields = nil
+		_, err := client.GetCharacter(defaultCtx, &raiderio.CharacterQuery{
+			Region:           raiderio.Regions.US,
+			Realm:            "illidan",
+			Name:             "highervalue",
+			MythicPlusScores: tc.scores,
+			Season:           tc.season,
+		})
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if tc.expectedErr != nil {
+			if len(fields) != 0 {
+				t.Fatalf("expected no request for an invalid season, got: %v", fields)
+			}
+			continue
+		}
+
+		if len(fields) != 1 || fields[0] != tc.expectedFields {
+			t.Fatalf("expected fields: %v, got: %v", tc.expectedFields, fields)
+		}
+	}
+}
+
+func TestGetCharactersOnRealm(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if name == "missing" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode":400,"error":"Bad Request","message":"Could not find requested character"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"` + name + `","realm":"Illidan"}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	names := []string{"highervalue", "thrall", "missing", "jaina", "sylvanas", "anduin"}
+
+	chars, errs := client.GetCharactersOnRealm(defaultCtx, raiderio.Regions.US, "illidan", names, &raiderio.CharacterQuery{Gear: true})
+	if len(chars) != 5 {
+		t.Fatalf("expected characters: %v, got: %v", 5, len(chars))
+	}
+
+	if chars["thrall"] == nil || chars["thrall"].Name != "thrall" {
+		t.Fatalf("expected character: %v, got: %v", "thrall", chars["thrall"])
+	}
+
+	if len(errs) != 1 || errs["missing"] != raiderio.ErrCharacterNotFound {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrCharacterNotFound, errs)
+	}
+
+	chars, errs = client.GetCharactersOnRealm(defaultCtx, raiderio.Regions.US, "", names, nil)
+	if len(chars) != 0 || len(errs) != len(names) || errs["thrall"] != raiderio.ErrInvalidRealm {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrInvalidRealm, errs["thrall"])
+	}
+}
+
+func TestFindCharacter(t *testing.T) {
+	canceled := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("region") {
+		case "eu":
+			w.Write([]byte(`{"name":"Highervalue","region":"eu"}`))
+		case "us":
+			select {
+			case <-r.Context().Done():
+				close(canceled)
+			case <-time.After(5 * time.Second):
+			}
+		case "kr":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode":400,"error":"Bad Request","message":"Failed to find realm"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode":400,"error":"Bad Request","message":"Could not find requested character"}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	char, region, err := client.FindCharacter(defaultCtx, "illidan", "highervalue",
+		[]*raiderio.Region{raiderio.Regions.US, raiderio.Regions.EU, raiderio.Regions.KR})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if char.Region != "eu" || region != raiderio.Regions.EU {
+		t.Fatalf("expected character found in region: %v, got: %v", raiderio.Regions.EU.Slug, region)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected in flight lookups to be canceled")
+	}
+
+	testCases := []struct {
+		regions     []*raiderio.Region
+		expectedErr error
+	}{
+		{regions: []*raiderio.Region{raiderio.Regions.KR, raiderio.Regions.TW}, expectedErr: raiderio.ErrCharacterNotFound},
+		{regions: []*raiderio.Region{raiderio.Regions.TW, raiderio.Regions.WORLD}, expectedErr: raiderio.ErrWorldRegionNotAllowed},
+		{regions: nil, expectedErr: raiderio.ErrInvalidRegion},
+	}
+
+	// realm and region swapped, the realm is never a valid realm in any region
+	_, _, err = client.FindCharacter(defaultCtx, "us", "highervalue", []*raiderio.Region{raiderio.Regions.KR, raiderio.Regions.TW})
+	if err != raiderio.ErrRealmLooksLikeRegion {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrRealmLooksLikeRegion, err)
+	}
+
+	for _, tc := range testCases {
+		_, _, err := client.FindCharacter(defaultCtx, "illidan", "highervalue", tc.regions)
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestCharacterExists(t *testing.T) {
+	var fields []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = append(fields, r.URL.Query().Get("fields"))
+		switch r.URL.Query().Get("name") {
+		case "missing":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode":400,"error":"Bad Request","message":"Could not find requested character"}`))
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"name":"Highervalue"}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	testCases := []struct {
+		realm          string
+		name           string
+		expectedExists bool
+		expectedErr    error
+	}{
+		{realm: "illidan", name: "highervalue", expectedExists: true},
+		{realm: "illidan", name: "missing", expectedExists: false},
+		{realm: "illidan", name: "broken", expectedErr: raiderio.ErrUnexpected},
+		{realm: "", name: "highervalue", expectedErr: raiderio.ErrInvalidRealm},
+	}
+
+	for _, tc := range testCases {
+		exists, err := client.CharacterExists(defaultCtx, raiderio.Regions.US, tc.realm, tc.name)
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if exists != tc.expectedExists {
+			t.Fatalf("%v expected exists: %v, got: %v", tc.name, tc.expectedExists, exists)
+		}
+	}
+
+	for _, f := range fields {
+		if f != "" {
+			t.Fatalf("expected no fields requested, got: %v", f)
+		}
+	}
+}
+
+func TestCharacterWarnings(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Highervalue","talentLoadout":{"loadout_text":"abc"},"gear":{},"covenant":null}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		cq               raiderio.CharacterQuery
+		expectedWarnings []string
+	}{
+		{cq: raiderio.CharacterQuery{TalentLoadout: true, Gear: true}, expectedWarnings: nil},
+		{cq: raiderio.CharacterQuery{Gear: true, Guild: true, RawFields: []string{"covenant", "mythic_plus_scores_by_season:current"}},
+			expectedWarnings: []string{
+				"requested field missing from response: guild",
+				"requested field missing from response: mythic_plus_scores_by_season:current",
+			}},
+	}
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	for _, tc := range testCases {
+		cq := tc.cq
+		cq.Region, cq.Realm, cq.Name = raiderio.Regions.US, "illidan", "highervalue"
+		char, err := client.GetCharacter(defaultCtx, &cq)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Join(char.Warnings, ",") != strings.Join(tc.expectedWarnings, ",") {
+			t.Fatalf("expected warnings: %v, got: %v", tc.expectedWarnings, char.Warnings)
+		}
+	}
+}
+
+func TestCharacterQueryMerge(t *testing.T) {
+	testCases := []struct {
+		cq          raiderio.CharacterQuery
+		other       raiderio.CharacterQuery
+		expected    raiderio.CharacterQuery
+		expectedErr error
+	}{
+		{
+			cq:       raiderio.CharacterQuery{Region: raiderio.Regions.US, Realm: "illidan", Name: "highervalue", Gear: true, RawFields: []string{"covenant"}},
+			other:    raiderio.CharacterQuery{Name: "Highervalue", TalentLoadout: true, RawFields: []string{"covenant", "raid_achievement_meta"}},
+			expected: raiderio.CharacterQuery{Region: raiderio.Regions.US, Realm: "illidan", Name: "highervalue", Gear: true, TalentLoadout: true, RawFields: []string{"covenant", "raid_achievement_meta"}},
+		},
+		{
+			cq:       raiderio.CharacterQuery{Guild: true},
+			other:    raiderio.CharacterQuery{Region: raiderio.Regions.EU, Realm: "draenor", Name: "thrall", MythicPlusScores: true},
+			expected: raiderio.CharacterQuery{Region: raiderio.Regions.EU, Realm: "draenor", Name: "thrall", Guild: true, MythicPlusScores: true},
+		},
+		{
+			cq:          raiderio.CharacterQuery{Region: raiderio.Regions.US, Realm: "illidan", Name: "highervalue"},
+			other:       raiderio.CharacterQuery{Region: raiderio.Regions.US, Realm: "stormrage", Name: "highervalue", Gear: true},
+			expected:    raiderio.CharacterQuery{Region: raiderio.Regions.US, Realm: "illidan", Name: "highervalue"},
+			expectedErr: raiderio.ErrQueryConflict,
+		},
+		{
+			cq:          raiderio.CharacterQuery{Name: "highervalue", Season: "season-tww-2"},
+			other:       raiderio.CharacterQuery{Name: "highervalue", Season: "season-tww-3"},
+			expected:    raiderio.CharacterQuery{Name: "highervalue", Season: "season-tww-2"},
+			expectedErr: raiderio.ErrQueryConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.cq.Merge(&tc.other)
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if tc.cq.Region != tc.expected.Region || tc.cq.Realm != tc.expected.Realm || tc.cq.Name != tc.expected.Name ||
+			tc.cq.Gear != tc.expected.Gear || tc.cq.TalentLoadout != tc.expected.TalentLoadout ||
+			tc.cq.Guild != tc.expected.Guild || tc.cq.MythicPlusScores != tc.expected.MythicPlusScores || tc.cq.Season != tc.expected.Season ||
+			strings.Join(tc.cq.RawFields, ",") != strings.Join(tc.expected.RawFields, ",") {
+			t.Fatalf("expected query: %+v, got: %+v", tc.expected, tc.cq)
+		}
+	}
+}
+
+func TestTierSetCount(t *testing.T) {
+	raiderio.TierSetItemIDs = map[int]bool{1001: true, 1002: true, 1003: true, 1004: true, 1005: true}
+	defer func() { raiderio.TierSetItemIDs = map[int]bool{} }()
+
+	testCases := []struct {
+		items         raiderio.Items
+		expectedCount int
+	}{
+		{items: raiderio.Items{
+			Head: raiderio.Item{ID: 1001}, Shoulder: raiderio.Item{ID: 1002}, Chest: raiderio.Item{ID: 2003},
+			Hands: raiderio.Item{ID: 1004}, Legs: raiderio.Item{ID: 1005}, Neck: raiderio.Item{ID: 1003},
+		}, expectedCount: 4},
+		{items: raiderio.Items{Head: raiderio.Item{ID: 1001}, Chest: raiderio.Item{ID: 1003}}, expectedCount: 2},
+		{items: raiderio.Items{}, expectedCount: 0},
+	}
+
+	for _, tc := range testCases {
+		char := raiderio.Character{Gear: raiderio.Gear{Items: tc.items}}
+		if char.TierSetCount() != tc.expectedCount {
+			t.Fatalf("expected tier set count: %v, got: %v", tc.expectedCount, char.TierSetCount())
+		}
+	}
+}
+
+func TestClassColorHex(t *testing.T) {
+	testCases := []struct {
+		class         string
+		expectedColor string
+	}{
+		{class: "Death Knight", expectedColor: "#C41E3A"},
+		{class: "demon-hunter", expectedColor: "#A330C9"},
+		{class: "Mage", expectedColor: "#3FC7EB"},
+		{class: "unknown", expectedColor: ""},
+	}
+
+	for _, tc := range testCases {
+		char := raiderio.Character{Class: tc.class}
+		if char.ClassColorHex() != tc.expectedColor || raiderio.ClassColorHex(tc.class) != tc.expectedColor {
+			t.Fatalf("%v expected color: %v, got: %v", tc.class, tc.expectedColor, char.ClassColorHex())
+		}
+	}
+
+	var scores raiderio.MythicPlusSeasonScores
+	err := json.Unmarshal([]byte(`{"season":"season-tww-3","scores":{"all":3120.5},
+		"segments":{"all":{"score":3120.5,"color":"#ff8000"},"dps":{"score":3001,"color":"#f8633b"}}}`), &scores)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if scores.Color() != "#ff8000" {
+		t.Fatalf("expected score color: %v, got: %v", "#ff8000", scores.Color())
+	}
+}
+
+func TestCharacterKey(t *testing.T) {
+	testCases := []struct {
+		char        raiderio.Character
+		expectedKey string
+	}{
+		{char: raiderio.Character{Region: "us", Realm: "Argent Dawn", Name: "Highervalue"}, expectedKey: "us/argent-dawn/highervalue"},
+		{char: raiderio.Character{Region: "US", Realm: "argent-dawn", Name: "highervalue"}, expectedKey: "us/argent-dawn/highervalue"},
+		{char: raiderio.Character{Region: "eu", Realm: "Ревущий фьорд", Name: "Тралл"}, expectedKey: "eu/howling-fjord/тралл"},
+		{char: raiderio.Character{Region: "eu", Realm: "Pozzo dell'Eternità", Name: "Ñàmë"}, expectedKey: "eu/pozzo-delleternita/ñàmë"},
+	}
+
+	for _, tc := range testCases {
+		if tc.char.Key() != tc.expectedKey {
+			t.Fatalf("expected key: %v, got: %v", tc.expectedKey, tc.char.Key())
+		}
+	}
+
+	cq := raiderio.CharacterQuery{Region: raiderio.Regions.US, Realm: "argent-dawn", Name: "HigherValue"}
+	if cq.Key() != testCases[0].expectedKey {
+		t.Fatalf("expected key: %v, got: %v", testCases[0].expectedKey, cq.Key())
+	}
+}
+
+func TestMeetsRequirements(t *testing.T) {
+	var geared, ungeared raiderio.Character
+	err := json.Unmarshal([]byte(`{"name":"Highervalue","gear":{"item_level_equipped":639},
+		"mythic_plus_scores_by_season":[{"season":"season-tww-2","scores":{"all":2850.5}}],
+		"raid_achievement_curve":[{"raid":"liberation-of-undermine","aotc":"2025-03-10T04:00:00Z","cutting_edge":null},
+			{"raid":"nerubar-palace","aotc":"2024-09-20T04:00:00Z","cutting_edge":"2024-10-01T04:00:00Z"}]}`), &geared)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		char          *raiderio.Character
+		req           raiderio.Requirements
+		expectedMeets bool
+		expectedUnmet []string
+	}{
+		{char: &geared, req: raiderio.Requirements{}, expectedMeets: true},
+		{char: &geared, req: raiderio.Requirements{
+			MinItemLevel:       635,
+			MinMythicPlusScore: 2800,
+			AheadOfTheCurve:    []string{"liberation-of-undermine", "nerubar-palace"},
+			CuttingEdge:        []string{"nerubar-palace"},
+		}, expectedMeets: true},
+		{char: &geared, req: raiderio.Requirements{
+			MinItemLevel:       640,
+			MinMythicPlusScore: 3000,
+			CuttingEdge:        []string{"liberation-of-undermine"},
+		}, expectedUnmet: []string{
+			"item level 639 below 640",
+			"mythic plus score 2850.5 below 3000.0",
+			"cutting edge missing for liberation-of-undermine",
+		}},
+		{char: &ungeared, req: raiderio.Requirements{
+			MinItemLevel:       600,
+			MinMythicPlusScore: 1,
+			AheadOfTheCurve:    []string{"nerubar-palace"},
+		}, expectedUnmet: []string{
+			"item level unknown, request Gear",
+			"mythic plus score unknown, request MythicPlusScores",
+			"ahead of the curve unknown for nerubar-palace, request raid_achievement_curve:nerubar-palace",
+		}},
+	}
+
+	for _, tc := range testCases {
+		meets, unmet := tc.char.MeetsRequirements(tc.req)
+		if meets != tc.expectedMeets {
+			t.Fatalf("expected meets requirements: %v, got: %v", tc.expectedMeets, meets)
+		}
+
+		if strings.Join(unmet, "|") != strings.Join(tc.expectedUnmet, "|") {
+			t.Fatalf("expected unmet: %v, got: %v", tc.expectedUnmet, unmet)
+		}
+	}
+}
+
+func TestGetCharacterEscapedRawFields(t *testing.T) {
+	var rawQuery string
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		query = r.URL.Query()
+		w.Write([]byte(`{"name":"Highervalue"}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		rawField         string
+		expectedRawQuery string
+		expectedFields   string
+	}{
+		{rawField: "mythic_plus_scores_by_season:current", expectedFields: "gear,mythic_plus_scores_by_season:current",
+			expectedRawQuery: "region=us&realm=illidan&name=highervalue&fields=gear%2Cmythic_plus_scores_by_season%3Acurrent"},
+		{rawField: "covenant&region=eu", expectedFields: "gear,covenant&region=eu",
+			expectedRawQuery: "region=us&realm=illidan&name=highervalue&fields=gear%2Ccovenant%26region%3Deu"},
+		{rawField: "covenant#top", expectedFields: "gear,covenant#top",
+			expectedRawQuery: "region=us&realm=illidan&name=highervalue&fields=gear%2Ccovenant%23top"},
+		{rawField: "raid achievement", expectedFields: "gear,raid achievement",
+			expectedRawQuery: "region=us&realm=illidan&name=highervalue&fields=gear%2Craid+achievement"},
+	}
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	for _, tc := range testCases {
+		_, err := client.GetCharacter(defaultCtx, &raiderio.CharacterQuery{
+			Region:    raiderio.Regions.US,
+			Realm:     "illidan",
+			Name:      "highervalue",
+			Gear:      true,
+			RawFields: []string{tc.rawField},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if rawQuery != tc.expectedRawQuery {
+			t.Fatalf("expected query: %v, got: %v", tc.expectedRawQuery, rawQuery)
+		}
+
+		if query.Get("fields") != tc.expectedFields || query.Get("region") != "us" || len(query) != 4 {
+			t.Fatalf("expected fields: %v, got query: %v", tc.expectedFields, query)
+		}
+	}
+}
+
+func TestGetCharacterEncodedName(t *testing.T) {
+	var rawQuery, name string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		name = r.URL.Query().Get("name")
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"name":"` + name + `","realm":"` + r.URL.Query().Get("realm") + `"}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		realm            string
+		name             string
+		expectedRawQuery string
+	}{
+		{realm: "hyjal", name: "Élyndra", expectedRawQuery: "region=eu&realm=hyjal&name=%C3%89lyndra"},
+		{realm: "blackmoore", name: "Größe", expectedRawQuery: "region=eu&realm=blackmoore&name=Gr%C3%B6%C3%9Fe"},
+		{realm: "pozzo-delleternità", name: "Ñandú", expectedRawQuery: "region=eu&realm=pozzo-delleternit%C3%A0&name=%C3%91and%C3%BA"},
+	}
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	for _, tc := range testCases {
+		profile, err := client.GetCharacter(defaultCtx, &raiderio.CharacterQuery{
+			Region: raiderio.Regions.EU,
+			Realm:  tc.realm,
+			Name:   tc.name,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if rawQuery != tc.expectedRawQuery {
+			t.Fatalf("expected query: %v, got: %v", tc.expectedRawQuery, rawQuery)
+		}
+
+		if profile.Name != tc.name || profile.Realm != tc.realm {
+			t.Fatalf("expected character: %v-%v, got: %v-%v", tc.name, tc.realm, profile.Name, profile.Realm)
+		}
+	}
+}
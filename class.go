@@ -0,0 +1,31 @@
+package raiderio
+
+// classColors maps class slugs to the class colors used in game
+var classColors = map[string]string{
+	"death-knight": "#C41E3A",
+	"demon-hunter": "#A330C9",
+	"druid":        "#FF7C0A",
+	"evoker":       "#33937F",
+	"hunter":       "#AAD372",
+	"mage":         "#3FC7EB",
+	"monk":         "#00FF98",
+	"paladin":      "#F48CBA",
+	"priest":       "#FFFFFF",
+	"rogue":        "#FFF468",
+	"shaman":       "#0070DD",
+	"warlock":      "#8788EE",
+	"warrior":      "#C69B6D",
+}
+
+// ClassColorHex returns the in game color of a class as a hex string,
+// ex: "Death Knight" or "death-knight" returns "#C41E3A"
+// Returns an empty string if the class is not recognized
+func ClassColorHex(class string) string {
+	return classColors[toSlug(class)]
+}
+
+// ClassColorHex returns the in game color of the character's class as a
+// hex string, or an empty string if the class is not recognized
+func (c *Character) ClassColorHex() string {
+	return ClassColorHex(c.Class)
+}
@@ -6,38 +6,102 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Base URL for the Raider.IO API
 const baseUrl string = "https://raider.io/api"
 
+// Version of the Raider.IO API used by default, appended to the base URL
+const apiVersion string = "v1"
+
+// Maximum number of requests a batch method sends to the api at once
+const maxConcurrentRequests int = 4
+
 // Client is the main struct for interacting with the Raider.IO API
 type Client struct {
 	ApiUrl     string
 	HttpClient *http.Client
+
+	baseUrl        string
+	apiVersion     string
+	mu             sync.Mutex
+	rawResponses   bool
+	lastRaw        []byte
+	lastRateLimit  RateLimitInfo
+	connTrace      func(ConnTrace)
+	strictDecoding bool
+	schemaWarnings func(endpoint string, unknownFields []string)
+	defaultRegion  *Region
+	defaultRealm   string
+	attemptTimeout time.Duration
+	etagCache      ETagCache
+	defaultParams  url.Values
+	requestID      func(context.Context) string
+	now            func() time.Time
+	static         staticData
 }
 
 // NewClient creates a new Client struct
-func NewClient() *Client {
+// Accepts optional ClientOptions to configure the client
+func NewClient(opts ...ClientOption) *Client {
 	var c Client
-	c.ApiUrl = baseUrl + "/v1"
-	c.HttpClient = &http.Client{}
+	c.baseUrl = baseUrl
+	c.apiVersion = apiVersion
+	c.HttpClient = &http.Client{CheckRedirect: LimitRedirects(maxRedirects)}
+	c.now = time.Now
+	for _, opt := range opts {
+		opt(&c)
+	}
+	c.ApiUrl = c.baseUrl + "/" + c.apiVersion
 	return &c
 }
 
+// clock returns the current time from the client's clock, see WithClock
+func (c *Client) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// regionRealm returns the region and realm of a query, replacing them with
+// the client's defaults when empty. Set values always win
+func (c *Client) regionRealm(region *Region, realm string) (*Region, string) {
+	if region == nil {
+		region = c.defaultRegion
+	}
+
+	if realm == "" {
+		realm = c.defaultRealm
+	}
+	return region, realm
+}
+
 // GetCharacter retrieves a character profile from the Raider.IO API
 // It returns an error if the API returns a non-200 status code, or if the
 // response body cannot be read or mapped to the CharacterProfile struct
 func (c *Client) GetCharacter(ctx context.Context, cq *CharacterQuery) (*Character, error) {
-	err := validateCharacterQuery(cq)
+	if cq == nil {
+		return nil, ErrNilQuery
+	}
+
+	q := *cq
+	q.Region, q.Realm = c.regionRealm(cq.Region, cq.Realm)
+	cq = &q
+
+	err := cq.validate()
 	if err != nil {
 		return nil, err
 	}
 
-	reqUrl := c.ApiUrl + "/characters/profile?region=" + cq.Region.Slug + "&realm=" + cq.Realm + "&name=" + cq.Name
+	reqUrl := c.ApiUrl + "/characters/profile?region=" + cq.Region.Slug + "&realm=" + url.QueryEscape(cq.Realm) + "&name=" + url.QueryEscape(cq.Name)
 	if cq.fields != nil && len(cq.fields) != 0 {
-		reqUrl += "&fields=" + strings.Join(cq.fields, ",")
+		reqUrl += "&fields=" + url.QueryEscape(strings.Join(cq.fields, ","))
 	}
 
 	body, err := c.getAPIResponse(ctx, reqUrl)
@@ -46,26 +110,183 @@ func (c *Client) GetCharacter(ctx context.Context, cq *CharacterQuery) (*Charact
 	}
 
 	var profile Character
-	err = json.Unmarshal(body, &profile)
+	err = unmarshalJSON(body, &profile, c.strictDecoding, "error unmarshalling character profile")
 	if err != nil {
-		return nil, errors.New("error unmarshalling character profile")
+		return nil, err
+	}
+	c.warnSchemaDrift("characters/profile", body, &profile)
+	profile.Warnings = missingFieldWarnings(body, cq.fields)
+
+	// characters with no runs this week may be missing the field entirely
+	if cq.MythicPlusWeeklyHighestRuns && profile.MythicPlusWeeklyHighestRuns == nil {
+		profile.MythicPlusWeeklyHighestRuns = []MythicPlusRun{}
+	}
+
+	if cq.MythicPlusPreviousWeeklyHighestRuns && profile.MythicPlusPreviousWeeklyHighestRuns == nil {
+		profile.MythicPlusPreviousWeeklyHighestRuns = []MythicPlusRun{}
 	}
 
 	return &profile, nil
 }
 
+// GetCharactersOnRealm retrieves the profiles of several characters on
+// the same realm, ex: importing a guild roster from a list of names
+// The api has no bulk endpoint, so one request is sent per name,
+// concurrently, with at most maxConcurrentRequests in flight
+// Optional fields are copied from opts, which may be nil. Its Region,
+// Realm and Name are ignored
+// Results and errors are keyed by name. If the region or realm is invalid
+// no requests are sent, and every name fails with the same error
+func (c *Client) GetCharactersOnRealm(ctx context.Context, region *Region, realm string, names []string, opts *CharacterQuery) (map[string]*Character, map[string]error) {
+	chars := map[string]*Character{}
+	errs := map[string]error{}
+	if opts == nil {
+		opts = &CharacterQuery{}
+	}
+	region, realm = c.regionRealm(region, realm)
+
+	err := validateRegionRealm(region, realm)
+	if err != nil {
+		for _, name := range names {
+			errs[name] = err
+		}
+		return chars, errs
+	}
+
+	var mu sync.Mutex
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for _, name := range names {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			errs[name] = wrapHttpError(ctx.Err())
+			mu.Unlock()
+			continue
+		}
+
+		cq := *opts
+		cq.Region, cq.Realm, cq.Name = region, realm, name
+		cq.RawFields = append([]string(nil), opts.RawFields...)
+
+		wg.Add(1)
+		go func(cq *CharacterQuery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			char, err := c.GetCharacter(ctx, cq)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[cq.Name] = err
+				return
+			}
+			chars[cq.Name] = char
+		}(&cq)
+	}
+	wg.Wait()
+
+	return chars, errs
+}
+
+// CharacterExists reports whether a character exists, requesting the
+// character's profile with no optional fields, the smallest response
+// ErrCharacterNotFound is reported as false with a nil error, other
+// errors, ex: ErrInvalidRealm, are returned as is
+func (c *Client) CharacterExists(ctx context.Context, region *Region, realm string, name string) (bool, error) {
+	_, err := c.GetCharacter(ctx, &CharacterQuery{Region: region, Realm: realm, Name: name})
+	if errors.Is(err, ErrCharacterNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindCharacter looks up a character whose region is unknown, ex: after a
+// region transfer. One request is sent per region, concurrently, and the
+// first character found is returned along with the region it was found in
+// The remaining requests are canceled once a character is found
+// Returns ErrCharacterNotFound if no region has the character. Regions in
+// which the api cannot find the realm count as not found. If any other
+// request fails, the error of the first such region in regions is returned
+// instead. Invalid realms, ex: ErrRealmLooksLikeRegion, fail immediately
+func (c *Client) FindCharacter(ctx context.Context, realm string, name string, regions []*Region) (*Character, *Region, error) {
+	if len(regions) == 0 {
+		return nil, nil, ErrInvalidRegion
+	}
+
+	// the realm is the same in every region, so it is validated once here,
+	// and an invalid realm is not mistaken for a realm missing in a region
+	_, r := c.regionRealm(nil, realm)
+	if r == "" {
+		return nil, nil, ErrInvalidRealm
+	}
+
+	if realmLooksLikeRegion(r) {
+		return nil, nil, ErrRealmLooksLikeRegion
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		i    int
+		char *Character
+		err  error
+	}
+
+	// buffered so lookups still in flight after a match do not block
+	results := make(chan result, len(regions))
+	for i, region := range regions {
+		go func(i int, region *Region) {
+			char, err := c.GetCharacter(ctx, &CharacterQuery{Region: region, Realm: realm, Name: name})
+			results <- result{i: i, char: char, err: err}
+		}(i, region)
+	}
+
+	errs := make([]error, len(regions))
+	for range regions {
+		r := <-results
+		if r.err == nil {
+			return r.char, regions[r.i], nil
+		}
+		errs[r.i] = r.err
+	}
+
+	// only the api's own realm error, not the wrapped validation errors,
+	// means the realm does not exist in that region
+	for _, err := range errs {
+		if !errors.Is(err, ErrCharacterNotFound) && err != ErrInvalidRealm {
+			return nil, nil, err
+		}
+	}
+	return nil, nil, ErrCharacterNotFound
+}
+
 // GetGuild retrieves a guild profile from the Raider.IO API
 // It returns an error if the API returns a non-200 status code, or if the
 // response body cannot be read or mapped to the GuildProfile struct
 func (c *Client) GetGuild(ctx context.Context, gq *GuildQuery) (*Guild, error) {
-	err := createGuildQuery(gq)
+	if gq == nil {
+		return nil, ErrNilQuery
+	}
+
+	q := *gq
+	q.Region, q.Realm = c.regionRealm(gq.Region, gq.Realm)
+	gq = &q
+
+	err := gq.validate()
 	if err != nil {
 		return nil, err
 	}
 
-	reqUrl := c.ApiUrl + "/guilds/profile?region=" + gq.Region.Slug + "&realm=" + gq.Realm + "&name=" + gq.Name
+	reqUrl := c.ApiUrl + "/guilds/profile?region=" + gq.Region.Slug + "&realm=" + url.QueryEscape(gq.Realm) + "&name=" + url.QueryEscape(gq.Name)
 	if gq.fields != nil && len(gq.fields) != 0 {
-		reqUrl += "&fields=" + strings.Join(gq.fields, ",")
+		reqUrl += "&fields=" + url.QueryEscape(strings.Join(gq.fields, ","))
 	}
 
 	body, err := c.getAPIResponse(ctx, reqUrl)
@@ -73,14 +294,76 @@ func (c *Client) GetGuild(ctx context.Context, gq *GuildQuery) (*Guild, error) {
 		return nil, err
 	}
 
-	profile, err := unmarshalGuild(body)
+	profile, err := unmarshalGuild(body, c.strictDecoding)
 	if err != nil {
 		return nil, err
 	}
+	c.warnSchemaDrift("guilds/profile", body, profile)
 
 	return profile, nil
 }
 
+// GetGuildMembersStream retrieves a guild's members from the Raider.IO API,
+// decoding the members one at a time and passing each to fn, so the full
+// member list is never held in memory
+// Optional fields on the GuildQuery are ignored, only members are requested
+// Returns the first error returned by fn, which stops decoding
+func (c *Client) GetGuildMembersStream(ctx context.Context, gq *GuildQuery, fn func(GuildMember) error) error {
+	if gq == nil {
+		return ErrNilQuery
+	}
+
+	gq = &GuildQuery{Region: gq.Region, Realm: gq.Realm, Name: gq.Name}
+	gq.Region, gq.Realm = c.regionRealm(gq.Region, gq.Realm)
+
+	err := gq.validate()
+	if err != nil {
+		return err
+	}
+
+	reqUrl := c.ApiUrl + "/guilds/profile?region=" + gq.Region.Slug + "&realm=" + url.QueryEscape(gq.Realm) + "&name=" + url.QueryEscape(gq.Name) +
+		"&fields=members"
+
+	resp, err := c.doAPIRequest(ctx, reqUrl, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeGuildMembers(resp.Body, fn)
+}
+
+// GetGuilds retrieves multiple guild profiles from the Raider.IO API
+// Requests are sent concurrently, with at most maxConcurrentRequests in flight
+// The returned guilds and errors are index aligned with the queries, so
+// guilds[i] and errs[i] are the result of queries[i]
+// Queries not yet sent when ctx is done fail with the context's error
+func (c *Client) GetGuilds(ctx context.Context, queries []*GuildQuery) ([]*Guild, []error) {
+	guilds := make([]*Guild, len(queries))
+	errs := make([]error, len(queries))
+
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, gq := range queries {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = wrapHttpError(ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, gq *GuildQuery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			guilds[i], errs[i] = c.GetGuild(ctx, gq)
+		}(i, gq)
+	}
+	wg.Wait()
+
+	return guilds, errs
+}
+
 // GetRaids retrieves a list of raids from the Raider.IO API
 // It returns an error if the API returns a non-200 status code, or if the
 // response body cannot be read or mapped to the Raids struct
@@ -93,10 +376,11 @@ func (c *Client) GetRaids(ctx context.Context, e Expansion) (*Raids, error) {
 	}
 
 	var raids Raids
-	err = json.Unmarshal(body, &raids)
+	err = unmarshalJSON(body, &raids, c.strictDecoding, "error unmarshalling raids")
 	if err != nil {
-		return nil, errors.New("error unmarshalling raids")
+		return nil, err
 	}
+	c.warnSchemaDrift("raiding/static-data", body, &raids)
 
 	return &raids, nil
 }
@@ -105,8 +389,23 @@ func (c *Client) GetRaids(ctx context.Context, e Expansion) (*Raids, error) {
 // It returns an error if the API returns a non-200 status code, or if the
 // response body cannot be read or mapped to the RaidRankings struct
 // Takes a RaidQuery struct as a parameter, in addition to context.Context
+// A realm or page with no ranked guilds is not an error, see IsEmpty
 func (c *Client) GetRaidRankings(ctx context.Context, rq *RaidQuery) (*RaidRankings, error) {
-	err := validateRaidRankingsQuery(rq)
+	if rq == nil {
+		return nil, ErrNilQuery
+	}
+
+	// the realm narrows the rankings, so only the default region is applied
+	q := *rq
+	q.Region, _ = c.regionRealm(rq.Region, "")
+	rq = &q
+
+	err := rq.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateStaticRaid(rq.Slug)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +414,7 @@ func (c *Client) GetRaidRankings(ctx context.Context, rq *RaidQuery) (*RaidRanki
 		"&difficulty=" + string(rq.Difficulty) + "&region=" + rq.Region.Slug
 
 	if rq.Realm != "" {
-		reqUrl += "&realm=" + rq.Realm
+		reqUrl += "&realm=" + url.QueryEscape(rq.Realm)
 	}
 
 	if rq.Limit != 0 {
@@ -132,26 +431,157 @@ func (c *Client) GetRaidRankings(ctx context.Context, rq *RaidQuery) (*RaidRanki
 	}
 
 	var rankings RaidRankings
-	err = json.Unmarshal(body, &rankings)
+	err = unmarshalJSON(body, &rankings, c.strictDecoding, "error unmarshalling raid rankings")
 	if err != nil {
-		return nil, errors.New("error unmarshalling raid rankings")
+		return nil, err
 	}
+	c.warnSchemaDrift("raiding/raid-rankings", body, &rankings)
 
 	return &rankings, nil
 }
 
+// GetRaidRankingsAllDifficulties retrieves a raid's rankings for normal,
+// heroic and mythic from the Raider.IO API, sending the three requests
+// concurrently. limit is passed to each request, 0 uses the api's default
+// Results and errors are keyed by difficulty. A failed difficulty does not
+// fail the others, its error is returned in the error map instead
+func (c *Client) GetRaidRankingsAllDifficulties(ctx context.Context, slug string, region *Region, limit int) (map[RaidDifficulty]*RaidRankings, map[RaidDifficulty]error) {
+	rankings := map[RaidDifficulty]*RaidRankings{}
+	errs := map[RaidDifficulty]error{}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, d := range []RaidDifficulty{Difficulty.NormalRaid, Difficulty.HeroicRaid, Difficulty.MythicRaid} {
+		wg.Add(1)
+		go func(d RaidDifficulty) {
+			defer wg.Done()
+			r, err := c.GetRaidRankings(ctx, &RaidQuery{Slug: slug, Difficulty: d, Region: region, Limit: limit})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[d] = err
+				return
+			}
+			rankings[d] = r
+		}(d)
+	}
+	wg.Wait()
+
+	return rankings, errs
+}
+
+// GetRaidRankingsForRealms retrieves a raid's rankings for several realms,
+// ex: the realms of a connected realm cluster, and merges them into one
+// leaderboard sorted by world rank
+// The api accepts a single realm per request, so one request is sent per
+// realm, concurrently, with at most maxConcurrentRequests in flight
+// rq.Realm is ignored. Guilds returned for more than one realm slug are
+// only included once, as returned for the first of those realms
+// A failed realm does not fail the others, its error is returned in the
+// error map, keyed by realm
+func (c *Client) GetRaidRankingsForRealms(ctx context.Context, rq *RaidQuery, realms []string) (*RaidRankings, map[string]error) {
+	merged := &RaidRankings{RaidRanking: []RaidRanking{}}
+	errs := map[string]error{}
+	if rq == nil {
+		for _, realm := range realms {
+			errs[realm] = ErrNilQuery
+		}
+		return merged, errs
+	}
+
+	// results are kept per realm and merged in realms order once every
+	// request is done, so duplicates resolve the same way on every call
+	var mu sync.Mutex
+	results := make([]*RaidRankings, len(realms))
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, realm := range realms {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			errs[realm] = wrapHttpError(ctx.Err())
+			mu.Unlock()
+			continue
+		}
+
+		q := *rq
+		q.Realm = realm
+
+		wg.Add(1)
+		go func(i int, q *RaidQuery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r, err := c.GetRaidRankings(ctx, q)
+			if err != nil {
+				mu.Lock()
+				errs[q.Realm] = err
+				mu.Unlock()
+				return
+			}
+			results[i] = r
+		}(i, &q)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+
+		for _, ranking := range r.RaidRanking {
+			key := rankingGuildKey(ranking)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged.RaidRanking = append(merged.RaidRanking, ranking)
+		}
+	}
+
+	merged.SortByRank()
+	return merged, errs
+}
+
+// rankingGuildKey identifies the guild of a raid ranking, by id when
+// the api returned one, otherwise by region, realm and name
+func rankingGuildKey(r RaidRanking) string {
+	if r.Guild.Id != 0 {
+		return fmt.Sprintf("%d", r.Guild.Id)
+	}
+	return r.Guild.Region.Slug + "/" + r.Guild.Realm.Slug + "/" + strings.ToLower(r.Guild.Name)
+}
+
 // GetGuildBossKill returns a guild's first kill of a given boss
+// The api only reports the first kill, there is no kill history, so later
+// kills of the same boss cannot be requested
 // Takes a context.Context object to facilitate timeout, and a GuildBossKillQuery
 // GuildBossKillQuery has only required fields for this request
 // returns a BossKill object
 func (c *Client) GetGuildBossKill(ctx context.Context, q *GuildBossKillQuery) (*BossKill, error) {
-	err := validateGuildBossKillQuery(q)
+	if q == nil {
+		return nil, ErrNilQuery
+	}
+
+	bq := *q
+	bq.Region, bq.Realm = c.regionRealm(q.Region, q.Realm)
+	q = &bq
+
+	err := q.validate()
 	if err != nil {
 		return nil, err
 	}
+
+	err = c.validateStaticBoss(q.RaidSlug, q.BossSlug)
+	if err != nil {
+		return nil, err
+	}
+
 	reqUrl := c.ApiUrl + "/guilds/boss-kill?raid=" + q.RaidSlug +
 		"&difficulty=" + string(q.Difficulty) + "&region=" + q.Region.Slug +
-		"&realm=" + q.Realm + "&guild=" + q.GuildName + "&boss=" + q.BossSlug
+		"&realm=" + url.QueryEscape(q.Realm) + "&guild=" + url.QueryEscape(q.GuildName) + "&boss=" + q.BossSlug
 
 	body, err := c.getAPIResponse(ctx, reqUrl)
 	if err != nil {
@@ -165,3 +595,271 @@ func (c *Client) GetGuildBossKill(ctx context.Context, q *GuildBossKillQuery) (*
 
 	return k, nil
 }
+
+// GuildRaidTimeline retrieves a guild's first kill of every boss of a raid,
+// sorted by the time each boss was defeated, reconstructing the guild's
+// progression through the raid
+// The raid's bosses are read from the preloaded static data, see
+// PreloadStaticData, otherwise the static data of each expansion is
+// requested until the raid is found
+// One boss kill request is sent per boss, concurrently, with at most
+// maxConcurrentRequests in flight. Bosses the guild has not killed are
+// skipped. If any other request fails, the error of the first such boss,
+// in raid order, is returned
+func (c *Client) GuildRaidTimeline(ctx context.Context, region *Region, realm string, guild string, raidSlug string, d RaidDifficulty) ([]BossKill, error) {
+	raid, err := c.raidBySlug(ctx, raidSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	kills := make([]*BossKill, len(raid.Encounters))
+	errs := make([]error, len(raid.Encounters))
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, e := range raid.Encounters {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = wrapHttpError(ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, boss string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			kills[i], errs[i] = c.GetGuildBossKill(ctx, &GuildBossKillQuery{
+				Region:     region,
+				Realm:      realm,
+				GuildName:  guild,
+				RaidSlug:   raidSlug,
+				BossSlug:   boss,
+				Difficulty: d,
+			})
+		}(i, e.Slug)
+	}
+	wg.Wait()
+
+	timeline := []BossKill{}
+	for i := range raid.Encounters {
+		// the api reports a boss the guild has not killed as not found
+		if errors.Is(errs[i], ErrInvalidBoss) {
+			continue
+		}
+
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		if kills[i].Kill.DefeatedAt.IsZero() {
+			continue
+		}
+		timeline = append(timeline, *kills[i])
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Kill.DefeatedAt.Before(timeline[j].Kill.DefeatedAt)
+	})
+	return timeline, nil
+}
+
+// raidBySlug looks up a raid in the preloaded static data, or requests the
+// static data of each expansion, newest first, when none is preloaded
+func (c *Client) raidBySlug(ctx context.Context, slug string) (*Raid, error) {
+	raid, loaded := c.findStaticRaid(slug)
+	if loaded {
+		if raid == nil {
+			return nil, ErrInvalidRaid
+		}
+		return raid, nil
+	}
+
+	for _, e := range []Expansion{Expansions.WarWithin, Expansions.Dragonflight, Expansions.Shadowlands,
+		Expansions.BattleForAzeroth, Expansions.Legion} {
+		raids, err := c.GetRaids(ctx, e)
+		if errors.Is(err, ErrUnsupportedExpac) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		raid, err := raids.GetRaidBySlug(slug)
+		if err == nil {
+			return raid, nil
+		}
+	}
+	return nil, ErrInvalidRaid
+}
+
+// GetMythicPlusRuns retrieves a mythic plus runs leaderboard from the Raider.IO API
+// It returns an error if the API returns a non-200 status code, or if the
+// response body cannot be read or mapped to the MythicPlusRuns struct
+// Takes a MythicPlusRunsQuery struct as a parameter, in addition to context.Context
+func (c *Client) GetMythicPlusRuns(ctx context.Context, q *MythicPlusRunsQuery) (*MythicPlusRuns, error) {
+	if q == nil {
+		return nil, ErrNilQuery
+	}
+
+	mq := *q
+	mq.Region, _ = c.regionRealm(q.Region, "")
+	q = &mq
+
+	err := q.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.validateStaticDungeon(q.Dungeon)
+	if err != nil {
+		return nil, err
+	}
+
+	reqUrl := c.ApiUrl + "/mythic-plus/runs?region=" + q.Region.Slug + "&dungeon=" + q.Dungeon
+
+	if q.Season != "" {
+		reqUrl += "&season=" + q.Season
+	}
+
+	if q.Affixes != "" {
+		reqUrl += "&affixes=" + q.Affixes
+	}
+
+	if q.Page != 0 {
+		reqUrl += "&page=" + fmt.Sprintf("%d", q.Page)
+	}
+
+	body, err := c.getAPIResponse(ctx, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := unmarshalMythicPlusRuns(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// GetMythicPlusStaticData retrieves a mythic plus season of an expansion,
+// including the season's dungeon pool, from the Raider.IO API
+// season is a season slug of the expansion, or "current" or "previous".
+// An empty season defaults to the current season, the most recently
+// started season in the api's response, see WithClock
+// Returns ErrInvalidSeason if the season is not a season of the expansion,
+// or the same errors as any other request
+func (c *Client) GetMythicPlusStaticData(ctx context.Context, e Expansion, season string) (*MythicPlusSeason, error) {
+	data, err := c.getMythicPlusStaticData(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveSeason(data.Seasons, season, c.clock())
+}
+
+// getMythicPlusStaticData retrieves every mythic plus season and dungeon
+// of an expansion
+func (c *Client) getMythicPlusStaticData(ctx context.Context, e Expansion) (*MythicPlusStaticData, error) {
+	reqUrl := c.ApiUrl + "/mythic-plus/static-data?expansion_id=" + fmt.Sprintf("%d", e)
+	body, err := c.getAPIResponse(ctx, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	var data MythicPlusStaticData
+	err = unmarshalJSON(body, &data, c.strictDecoding, "error unmarshalling mythic plus static data")
+	if err != nil {
+		return nil, err
+	}
+	c.warnSchemaDrift("mythic-plus/static-data", body, &data)
+
+	return &data, nil
+}
+
+// GetMythicPlusAffixes retrieves the mythic plus affixes active this week
+// in a region from the Raider.IO API
+// The api only reports the current week, see AffixesForWeek for others
+// It returns an error if the API returns a non-200 status code, or if the
+// response body cannot be read or mapped to the MythicPlusAffixes struct
+func (c *Client) GetMythicPlusAffixes(ctx context.Context, region *Region) (*MythicPlusAffixes, error) {
+	region, _ = c.regionRealm(region, "")
+	if region == nil {
+		return nil, ErrInvalidRegion
+	}
+
+	reqUrl := c.ApiUrl + "/mythic-plus/affixes?region=" + region.Slug + "&locale=en"
+	body, err := c.getAPIResponse(ctx, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	var affixes MythicPlusAffixes
+	err = unmarshalJSON(body, &affixes, c.strictDecoding, "error unmarshalling mythic plus affixes")
+	if err != nil {
+		return nil, err
+	}
+	c.warnSchemaDrift("mythic-plus/affixes", body, &affixes)
+
+	return &affixes, nil
+}
+
+// GetRaw retrieves an arbitrary endpoint from the Raider.IO API, for
+// endpoints the library does not model yet, ex: "mythic-plus/season-cutoffs"
+// path is relative to the client's api url, and params are sent as the
+// query string. The response is returned undecoded
+// Returns ErrInvalidPath if the path is absolute or leaves the api url,
+// otherwise the same errors as any other request
+func (c *Client) GetRaw(ctx context.Context, path string, params url.Values) (json.RawMessage, error) {
+	if !relativeApiPath(path) {
+		return nil, ErrInvalidPath
+	}
+
+	reqUrl := c.ApiUrl + "/" + strings.TrimPrefix(path, "/")
+	if len(params) != 0 {
+		reqUrl += "?" + params.Encode()
+	}
+
+	body, err := c.getAPIResponse(ctx, reqUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if !json.Valid(body) {
+		return nil, errors.New("error unmarshalling raw response")
+	}
+
+	return json.RawMessage(body), nil
+}
+
+// relativeApiPath reports whether path is a plain path below the api url,
+// with no scheme, host, query, or parent directory segments
+func relativeApiPath(path string) bool {
+	if path == "" || strings.HasPrefix(path, "//") || strings.ContainsAny(path, "?#\\") {
+		return false
+	}
+
+	u, err := url.Parse(path)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.User != nil {
+		return false
+	}
+
+	// u.Path is unescaped, so escaped segments such as %2e%2e are caught
+	for _, segment := range strings.Split(path+"/"+u.Path, "/") {
+		if segment == ".." || segment == "." {
+			return false
+		}
+	}
+	return true
+}
+
+// Ping checks that the Raider.IO API is reachable by requesting
+// static raid data for a fixed expansion, and discarding the response
+// It returns nil when the api responds successfully, ErrUnauthorized if the
+// api rejects the request, or the same errors as any other request
+func (c *Client) Ping(ctx context.Context) error {
+	reqUrl := c.ApiUrl + "/raiding/static-data?expansion_id=" + fmt.Sprintf("%d", Expansions.WarWithin)
+	_, err := c.getAPIResponse(ctx, reqUrl)
+	return err
+}
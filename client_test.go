@@ -2,6 +2,8 @@ package raiderio_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -32,6 +34,7 @@ func TestNewClient(t *testing.T) {
 func TestGetCharacterProfile(t *testing.T) {
 	testCases := []struct {
 		timeout        bool
+		canceled       bool
 		region         *raiderio.Region
 		realm          string
 		name           string
@@ -42,10 +45,14 @@ func TestGetCharacterProfile(t *testing.T) {
 		{region: raiderio.Regions.US, realm: "", name: "highervalue", expectedErrMsg: "invalid realm"},
 		{region: raiderio.Regions.US, realm: "illidan", name: "", expectedErrMsg: "invalid character name"},
 		{region: nil, realm: "illidan", name: "highervalue", expectedErrMsg: "invalid region"},
+		{region: raiderio.Regions.WORLD, realm: "illidan", name: "highervalue", expectedErrMsg: "world region not allowed for this request"},
 		{region: &raiderio.Region{Slug: "badregion"}, realm: "illidan", name: "impossiblecharactername", expectedErrMsg: "invalid region"},
 		{region: raiderio.Regions.US, realm: "illidan", name: "impossiblecharactername", expectedErrMsg: "character not found"},
 		{region: raiderio.Regions.US, realm: "invalidrealm", name: "highervalue", expectedErrMsg: "invalid realm"},
+		{region: raiderio.Regions.US, realm: "us", name: "highervalue",
+			expectedErrMsg: "invalid realm: realm is a region slug, check the realm and region are not swapped"},
 		{timeout: true, region: raiderio.Regions.US, realm: "illidan", name: "highervalue", expectedErrMsg: "raiderio api request timeout"},
+		{canceled: true, region: raiderio.Regions.US, realm: "illidan", name: "highervalue", expectedErrMsg: "raiderio api request canceled"},
 	}
 
 	for _, tc := range testCases {
@@ -56,6 +63,11 @@ func TestGetCharacterProfile(t *testing.T) {
 			defer cancel()
 		}
 
+		if tc.canceled {
+			ctx, cancel = context.WithCancel(defaultCtx)
+			cancel()
+		}
+
 		profile, err := c.GetCharacter(ctx, &raiderio.CharacterQuery{
 			Region: tc.region,
 			Realm:  tc.realm,
@@ -128,6 +140,39 @@ func TestGetCharacterWTalents(t *testing.T) {
 	}
 }
 
+func TestGetCharacterWGuild(t *testing.T) {
+	cq := raiderio.CharacterQuery{
+		Region: raiderio.Regions.US,
+		Realm:  "illidan",
+		Name:   "highervalue",
+		Guild:  true,
+	}
+
+	var fields string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = r.URL.Query().Get("fields")
+		w.Write([]byte(`{"name":"Highervalue","realm":"Illidan","guild":{"name":"Warpath","realm":"Illidan"}}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	profile, err := client.GetCharacter(defaultCtx, &cq)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fields != "guild" {
+		t.Fatalf("expected fields: guild, got: %v", fields)
+	}
+
+	expected := raiderio.CharacterGuild{Name: "Warpath", Realm: "Illidan"}
+	if profile.Guild != expected {
+		t.Fatalf("expected guild: %+v, got: %+v", expected, profile.Guild)
+	}
+}
+
 func TestGetGuild(t *testing.T) {
 	testCases := []struct {
 		timeout        bool
@@ -141,6 +186,7 @@ func TestGetGuild(t *testing.T) {
 		{region: raiderio.Regions.US, realm: "", name: "warpath", expectedErrMsg: "invalid realm"},
 		{region: raiderio.Regions.US, realm: "illidan", name: "", expectedErrMsg: "invalid guild name"},
 		{region: nil, realm: "illidan", name: "highervalue", expectedErrMsg: "invalid region"},
+		{region: raiderio.Regions.WORLD, realm: "illidan", name: "warpath", expectedErrMsg: "world region not allowed for this request"},
 		{region: &raiderio.Region{Slug: "badregion"}, realm: "illidan", name: "warpath", expectedErrMsg: "invalid region"},
 		{region: raiderio.Regions.US, realm: "illidan", name: "impossible_guild_name", expectedErrMsg: "guild not found"},
 		{region: raiderio.Regions.US, realm: "invalidrealm", name: "highervalue", expectedErrMsg: "invalid realm"},
@@ -171,6 +217,32 @@ func TestGetGuild(t *testing.T) {
 	}
 }
 
+func TestGetGuilds(t *testing.T) {
+	queries := []*raiderio.GuildQuery{
+		{Region: raiderio.Regions.US, Realm: "illidan", Name: "warpath"},
+		nil,
+		{Region: raiderio.Regions.US, Realm: "", Name: "warpath"},
+		{Region: raiderio.Regions.US, Realm: "illidan", Name: ""},
+	}
+	expectedErrMsgs := []string{"", "query must not be nil", "invalid realm", "invalid guild name"}
+	expectedNames := []string{"Warpath", "", "", ""}
+
+	guilds, errs := c.GetGuilds(defaultCtx, queries)
+	if len(guilds) != len(queries) || len(errs) != len(queries) {
+		t.Fatalf("expected %d results, got: %d guilds, %d errors", len(queries), len(guilds), len(errs))
+	}
+
+	for i := range queries {
+		if errs[i] != nil && errs[i].Error() != expectedErrMsgs[i] {
+			t.Fatalf("query %d expected error: %v, got: %v", i, expectedErrMsgs[i], errs[i].Error())
+		}
+
+		if errs[i] == nil && guilds[i].Name != expectedNames[i] {
+			t.Fatalf("query %d guild name expected: %v, got: %v", i, expectedNames[i], guilds[i].Name)
+		}
+	}
+}
+
 func TestGetGuildWMembers(t *testing.T) {
 	testCases := []struct {
 		region *raiderio.Region
@@ -195,6 +267,10 @@ func TestGetGuildWMembers(t *testing.T) {
 		if !(len(profile.Members) > 0) {
 			t.Fatalf("Error getting guild members")
 		}
+
+		if profile.Members[0].Class == "" {
+			t.Fatalf("guild member class expected to not be empty")
+		}
 	}
 
 }
@@ -461,6 +537,107 @@ func TestGetRaidRankings(t *testing.T) {
 	}
 }
 
+func TestPing(t *testing.T) {
+	testCases := []struct {
+		timeout        bool
+		expectedErrMsg string
+	}{
+		{},
+		{timeout: true, expectedErrMsg: "raiderio api request timeout"},
+	}
+
+	for _, tc := range testCases {
+		ctx := defaultCtx
+		var cancel context.CancelFunc
+		if tc.timeout {
+			ctx, cancel = context.WithTimeout(ctx, time.Millisecond*1)
+			defer cancel()
+		}
+
+		err := c.Ping(ctx)
+		if err != nil && err.Error() != tc.expectedErrMsg {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErrMsg, err.Error())
+		}
+	}
+}
+
+func TestNilQuery(t *testing.T) {
+	testCases := []struct {
+		endpoint string
+		call     func() error
+	}{
+		{endpoint: "GetCharacter", call: func() error {
+			_, err := c.GetCharacter(defaultCtx, nil)
+			return err
+		}},
+		{endpoint: "GetGuild", call: func() error {
+			_, err := c.GetGuild(defaultCtx, nil)
+			return err
+		}},
+		{endpoint: "GetRaidRankings", call: func() error {
+			_, err := c.GetRaidRankings(defaultCtx, nil)
+			return err
+		}},
+		{endpoint: "GetGuildBossKill", call: func() error {
+			_, err := c.GetGuildBossKill(defaultCtx, nil)
+			return err
+		}},
+		{endpoint: "GetMythicPlusRuns", call: func() error {
+			_, err := c.GetMythicPlusRuns(defaultCtx, nil)
+			return err
+		}},
+	}
+
+	for _, tc := range testCases {
+		err := tc.call()
+		if err != raiderio.ErrNilQuery {
+			t.Fatalf("%v expected error: %v, got: %v", tc.endpoint, raiderio.ErrNilQuery, err)
+		}
+	}
+}
+
+func TestGetMythicPlusRuns(t *testing.T) {
+	testCases := []struct {
+		timeout        bool
+		season         string
+		region         *raiderio.Region
+		dungeon        string
+		page           int
+		expectedErrMsg string
+	}{
+		{season: "season-tww-2", region: raiderio.Regions.WORLD, dungeon: "all"},
+		{season: "season-tww-2", region: nil, dungeon: "all", expectedErrMsg: "invalid region"},
+		{season: "season-tww-2", region: raiderio.Regions.US, dungeon: "", expectedErrMsg: "invalid dungeon"},
+		{season: "season-tww-2", region: raiderio.Regions.US, dungeon: "all", page: -1, expectedErrMsg: "page must be a positive int"},
+		{season: "season-tww-9", region: raiderio.Regions.US, dungeon: "all", expectedErrMsg: "invalid season"},
+		{timeout: true, season: "season-tww-2", region: raiderio.Regions.US, dungeon: "all",
+			expectedErrMsg: "raiderio api request timeout"},
+	}
+
+	for _, tc := range testCases {
+		ctx := defaultCtx
+		var cancel context.CancelFunc
+		if tc.timeout {
+			ctx, cancel = context.WithTimeout(ctx, time.Millisecond*1)
+			defer cancel()
+		}
+
+		runs, err := c.GetMythicPlusRuns(ctx, &raiderio.MythicPlusRunsQuery{
+			Season:  tc.season,
+			Region:  tc.region,
+			Dungeon: tc.dungeon,
+			Page:    tc.page,
+		})
+		if err != nil && err.Error() != tc.expectedErrMsg {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErrMsg, err.Error())
+		}
+
+		if err == nil && len(runs.Rankings) == 0 {
+			t.Fatalf("expected mythic plus rankings to not be empty")
+		}
+	}
+}
+
 // Tests if character is a part of the particular boss kill
 func killIncludesCharacter(k *raiderio.BossKill, c string) bool {
 	for _, v := range k.Roster {
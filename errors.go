@@ -1,34 +1,61 @@
 package raiderio
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 )
 
 // Errors that the api produces
 var (
-	ErrInvalidRegion     = errors.New("invalid region")
-	ErrInvalidRealm      = errors.New("invalid realm")
-	ErrInvalidCharName   = errors.New("invalid character name")
-	ErrInvalidGuildName  = errors.New("invalid guild name")
-	ErrInvalidRaidName   = errors.New("invalid raid name")
-	ErrInvalidRaidDiff   = errors.New("invalid raid difficulty")
-	ErrInvalidRaid       = errors.New("invalid raid")
-	ErrFieldMissing      = errors.New("field missing from api response")
-	ErrCharacterNotFound = errors.New("character not found")
-	ErrGuildNotFound     = errors.New("guild not found")
-	ErrUnsupportedExpac  = errors.New("unsupported expansion")
-	ErrLimitOutOfBounds  = errors.New("limit must be a positive int")
-	ErrPageOutOfBounds   = errors.New("page must be a positive int")
-	ErrInvalidBoss       = errors.New("invalid boss")
-	ErrInvalidQuery      = errors.New("invalid query")
-	ErrApiTimeout        = errors.New("raiderio api request timeout")
-	ErrUnexpected        = errors.New("unexpected error")
+	ErrInvalidRegion         = errors.New("invalid region")
+	ErrWorldRegionNotAllowed = errors.New("world region not allowed for this request")
+	ErrInvalidRealm          = errors.New("invalid realm")
+	ErrRealmLooksLikeRegion  = fmt.Errorf("%w: realm is a region slug, check the realm and region are not swapped", ErrInvalidRealm)
+	ErrWorldRegionRealm      = fmt.Errorf("%w: realm not allowed with the world region", ErrInvalidRealm)
+	ErrInvalidCharName       = errors.New("invalid character name")
+	ErrInvalidGuildName      = errors.New("invalid guild name")
+	ErrInvalidRaidName       = errors.New("invalid raid name")
+	ErrInvalidRaidDiff       = errors.New("invalid raid difficulty")
+	ErrInvalidRaid           = errors.New("invalid raid")
+	ErrInvalidSummary        = errors.New("invalid raid progression summary")
+	ErrFieldMissing          = errors.New("field missing from api response")
+	ErrDecode                = errors.New("api response does not match expected schema")
+	ErrCharacterNotFound     = errors.New("character not found")
+	ErrGuildNotFound         = errors.New("guild not found")
+	ErrUnsupportedExpac      = errors.New("unsupported expansion")
+	ErrLimitOutOfBounds      = errors.New("limit must be a positive int")
+	ErrLimitTooLarge         = fmt.Errorf("limit must be at most %d", MaxRaidRankingLimit)
+	ErrPageOutOfBounds       = errors.New("page must be a positive int")
+	ErrInvalidBoss           = errors.New("invalid boss")
+	ErrInvalidDungeon        = errors.New("invalid dungeon")
+	ErrInvalidSeason         = errors.New("invalid season")
+	ErrUnknownAffixes        = errors.New("affixes not found in affix rotation")
+	ErrInvalidQuery          = errors.New("invalid query")
+	ErrScopedRaidProgression = fmt.Errorf("%w: raid_progression cannot be scoped by expansion", ErrInvalidQuery)
+	ErrNilQuery              = errors.New("query must not be nil")
+	ErrQueryConflict         = errors.New("queries are for different characters")
+	ErrInvalidPath           = errors.New("path must be relative to the api url")
+	ErrApiTimeout            = errors.New("raiderio api request timeout")
+	ErrApiCanceled           = errors.New("raiderio api request canceled")
+	ErrUnauthorized          = errors.New("raiderio api request unauthorized")
+	ErrRateLimited           = errors.New("raiderio api request rate limited")
+	ErrResponseRead          = errors.New("error reading response body")
+	ErrTooManyRedirects      = errors.New("raiderio api request stopped after too many redirects")
+	ErrCrossHostRedirect     = errors.New("raiderio api request redirected to another host")
+	ErrRedirectNotFollowed   = errors.New("raiderio api request redirect not followed")
+	ErrUnexpected            = errors.New("unexpected error")
 )
 
 // Turns api errors into standardized go errors with
 // consistent error messages
 func wrapApiError(responseBody *apiErrorResponse) error {
+	if responseBody.StatusCode == http.StatusUnauthorized || responseBody.StatusCode == http.StatusForbidden {
+		return ErrUnauthorized
+	}
+
 	if strings.Contains(responseBody.Message, "Failed to find region") {
 		return ErrInvalidRegion
 	}
@@ -64,9 +91,129 @@ func wrapApiError(responseBody *apiErrorResponse) error {
 	return ErrUnexpected
 }
 
+// Turns http client errors into standardized go errors
+// A canceled context is reported separately from a timeout, so callers
+// can tell a deliberate cancellation apart from a slow api
 func wrapHttpError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return ErrApiCanceled
+	}
+
+	if errors.Is(err, ErrTooManyRedirects) {
+		return ErrTooManyRedirects
+	}
+
+	if errors.Is(err, ErrCrossHostRedirect) {
+		return ErrCrossHostRedirect
+	}
+
+	var policyErr *redirectPolicyError
+	if errors.As(err, &policyErr) {
+		return policyErr
+	}
+
 	if strings.Contains(err.Error(), "context deadline exceeded") {
 		return ErrApiTimeout
 	}
 	return ErrUnexpected
 }
+
+// redirectPolicyError is returned when a redirect policy set
+// WithRedirectPolicy rejects a redirect. errors.Is reports true for the
+// error returned by the policy
+type redirectPolicyError struct {
+	err error
+}
+
+func (e *redirectPolicyError) Error() string {
+	return "raiderio api request redirect rejected: " + e.err.Error()
+}
+
+func (e *redirectPolicyError) Unwrap() error {
+	return e.err
+}
+
+// wrapReadError turns an error reading a response body into a
+// responseReadError. A request whose deadline passes, or which is canceled,
+// while the body is read returns ErrApiTimeout or ErrApiCanceled instead
+func wrapReadError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) ||
+		strings.Contains(err.Error(), "context deadline exceeded") {
+		return wrapHttpError(err)
+	}
+	return &responseReadError{err: err}
+}
+
+// responseReadError is returned when a response body cannot be read, ex:
+// the connection dropped mid body. errors.Is reports true for both
+// ErrResponseRead and the underlying error, ex: io.ErrUnexpectedEOF
+type responseReadError struct {
+	err error
+}
+
+func (e *responseReadError) Error() string {
+	return ErrResponseRead.Error() + ": " + e.err.Error()
+}
+
+func (e *responseReadError) Is(target error) bool {
+	return target == ErrResponseRead
+}
+
+func (e *responseReadError) Unwrap() error {
+	return e.err
+}
+
+// CombineErrors combines the errors of a batch operation into a single error,
+// skipping nil errors. Returns nil when every error is nil
+// errors.Is and errors.As match any of the combined errors, ex:
+// errors.Is(err, ErrCharacterNotFound) is true if any request was not found
+// The message of the combined error is the messages of each error, one per line
+func CombineErrors(errs []error) error {
+	var combined combinedError
+	for _, err := range errs {
+		if err != nil {
+			combined.errs = append(combined.errs, err)
+		}
+	}
+
+	if len(combined.errs) == 0 {
+		return nil
+	}
+	return &combined
+}
+
+// combinedError is returned by CombineErrors
+// It implements Is and As itself, as errors.Join is unavailable before go 1.20
+type combinedError struct {
+	errs []error
+}
+
+func (e *combinedError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e *combinedError) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *combinedError) As(target interface{}) bool {
+	for _, err := range e.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *combinedError) Unwrap() []error {
+	return e.errs
+}
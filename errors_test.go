@@ -0,0 +1,101 @@
+package raiderio_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestCombineErrors(t *testing.T) {
+	testCases := []struct {
+		errs        []error
+		expectedNil bool
+		expectedMsg string
+		expectedIs  []error
+	}{
+		{errs: nil, expectedNil: true},
+		{errs: []error{nil, nil}, expectedNil: true},
+		{
+			errs:        []error{nil, raiderio.ErrCharacterNotFound},
+			expectedMsg: raiderio.ErrCharacterNotFound.Error(),
+			expectedIs:  []error{raiderio.ErrCharacterNotFound},
+		},
+		{
+			errs:        []error{raiderio.ErrInvalidRealm, nil, raiderio.ErrRealmLooksLikeRegion},
+			expectedMsg: raiderio.ErrInvalidRealm.Error() + "\n" + raiderio.ErrRealmLooksLikeRegion.Error(),
+			expectedIs:  []error{raiderio.ErrInvalidRealm, raiderio.ErrRealmLooksLikeRegion},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := raiderio.CombineErrors(tc.errs)
+		if (err == nil) != tc.expectedNil {
+			t.Fatalf("expected nil error: %v, got: %v", tc.expectedNil, err)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if err.Error() != tc.expectedMsg {
+			t.Fatalf("expected message: %q, got: %q", tc.expectedMsg, err.Error())
+		}
+
+		for _, target := range tc.expectedIs {
+			if !errors.Is(err, target) {
+				t.Fatalf("expected combined error to match: %v", target)
+			}
+		}
+
+		if errors.Is(err, raiderio.ErrGuildNotFound) {
+			t.Fatalf("expected combined error not to match: %v", raiderio.ErrGuildNotFound)
+		}
+	}
+}
+
+func TestCombineErrorsAs(t *testing.T) {
+	err := raiderio.CombineErrors([]error{raiderio.ErrInvalidRegion, &raiderio.RateLimitError{Message: "slow down"}})
+
+	var rl *raiderio.RateLimitError
+	if !errors.As(err, &rl) || rl.Message != "slow down" {
+		t.Fatalf("expected rate limit error, got: %v", err)
+	}
+
+	if rl.RetryAfter() != time.Duration(0) {
+		t.Fatalf("expected no retry after, got: %v", rl.RetryAfter())
+	}
+}
+
+func TestApiErrorStatus(t *testing.T) {
+	testCases := []struct {
+		status      int
+		response    string
+		expectedErr error
+	}{
+		{status: http.StatusUnauthorized, response: `{"message":"Unauthorized"}`, expectedErr: raiderio.ErrUnauthorized},
+		{status: http.StatusForbidden, response: `{"message":"Forbidden"}`, expectedErr: raiderio.ErrUnauthorized},
+		{status: http.StatusUnauthorized, response: `Unauthorized`, expectedErr: raiderio.ErrUnauthorized},
+		{status: http.StatusBadRequest, response: `{"statusCode":400,"message":"Failed to find realm"}`, expectedErr: raiderio.ErrInvalidRealm},
+		{status: http.StatusInternalServerError, response: `{"message":"Internal Server Error"}`, expectedErr: raiderio.ErrUnexpected},
+	}
+
+	for _, tc := range testCases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			w.Write([]byte(tc.response))
+		}))
+
+		client := raiderio.NewClient()
+		client.ApiUrl = ts.URL
+		_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		ts.Close()
+
+		if !errors.Is(err, tc.expectedErr) {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+	}
+}
@@ -3,6 +3,9 @@ package raiderio
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"sort"
+	"strings"
 )
 
 // GuildQuery is a struct that represents the query parameters
@@ -15,55 +18,312 @@ type GuildQuery struct {
 	Members         bool
 	RaidProgression bool
 	RaidRankings    bool
-	fields          []string
+
+	// RawFields are appended to the requested fields as is, in order,
+	// after the fields requested by the options above. Use it for fields
+	// the library does not model, including colon scoped fields
+	// raid_progression cannot be scoped by expansion, RaidProgression
+	// returns every raid, see GuildRaidProgression.All
+	RawFields []string
+	fields    []string
 }
 
 // Guild is a struct that represents the response from
 // a guild profile request
 type Guild struct {
-	Name            string                      `json:"name"`
-	Faction         string                      `json:"faction"`
-	Region          string                      `json:"region"`
-	Realm           string                      `json:"realm"`
-	LastCrawledAt   string                      `json:"last_crawled_at"`
-	ProfileUrl      string                      `json:"profile_url"`
-	Members         []Member                    `json:"members"`
-	RaidProgression GuildRaidProgression        `json:"raid_progression"`
-	RaidRankings    map[string]GuildRaidRanking `json:"raid_rankings"`
-}
-
-// Member is a struct that represents a member of a guild
+	Name            string               `json:"name"`
+	Faction         string               `json:"faction"`
+	Region          string               `json:"region"`
+	Realm           string               `json:"realm"`
+	LastCrawledAt   string               `json:"last_crawled_at"`
+	ProfileUrl      string               `json:"profile_url"`
+	Members         []GuildMember        `json:"members"`
+	RaidProgression GuildRaidProgression `json:"raid_progression"`
+	RaidRankings    GuildRaidRankings    `json:"raid_rankings"`
+
+	// MemberCount is the number of members, len(Members)
+	// The api has no field for the member count alone, so it is 0 unless
+	// the guild is requested with Members
+	MemberCount int `json:"-"`
+}
+
+// GuildMember is a struct that represents a member of a guild
 // in a guild profile response
-type Member struct {
+// Name, Class, Level, Realm and Region are copied from the member's
+// Character when the guild profile is unmarshalled
+type GuildMember struct {
 	Rank      int       `json:"rank"`
+	Name      string    `json:"-"`
+	Class     string    `json:"-"`
+	Level     int       `json:"-"`
+	Realm     string    `json:"-"`
+	Region    string    `json:"-"`
 	Character Character `json:"character"`
 }
 
+// Member is the previous name of GuildMember, kept for compatibility
+type Member = GuildMember
+
+// resolve copies the commonly used character fields onto the member
+func (m *GuildMember) resolve() {
+	m.Name = m.Character.Name
+	m.Class = m.Character.Class
+	m.Level = m.Character.Level
+	m.Realm = m.Character.Realm
+	m.Region = m.Character.Region
+}
+
+// SortedMembers returns the guild's members sorted by rank, then by name
+// Members listed more than once, the same name, realm and region, are
+// only included once. The guild's Members are not modified
+// Requires the guild to be requested with Members
+func (g *Guild) SortedMembers() []GuildMember {
+	members := make([]GuildMember, 0, len(g.Members))
+	seen := map[string]bool{}
+	for _, m := range g.Members {
+		key := strings.ToLower(m.Region + "/" + m.Realm + "/" + m.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		members = append(members, m)
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].Rank != members[j].Rank {
+			return members[i].Rank < members[j].Rank
+		}
+		return members[i].Name < members[j].Name
+	})
+	return members
+}
+
 // RaidProgression is a struct that contains the raid progression of a guild
 // in a guild profile response
-// Currently supports Dragonflight raids
+// The named fields cover Dragonflight raids, All holds every raid the api
+// returned, keyed by raid slug, including raids from other expansions
 type GuildRaidProgression struct {
 	Amirdrassil          RaidProgression `json:"amirdrassil-amirdrassil-the-dreams-hope"`
 	Aberrus              RaidProgression `json:"aberrus-the-shadowed-crucible"`
 	VaultOfTheIncarnates RaidProgression `json:"vault-of-the-incarnates"`
+
+	All map[string]RaidProgression `json:"-"`
 }
 
-// createGuildQuery creates and validates a GuildQuery struct
-// It returns an error if any of the required parameters are empty
-// or if the fields are invalid
-func createGuildQuery(gq *GuildQuery) error {
-	if gq.Region == nil {
-		return ErrInvalidRegion
+// UnmarshalJSON decodes the named raids, and every raid into All
+// An empty array, returned by the api for guilds with no progression,
+// decodes as no raids
+func (p *GuildRaidProgression) UnmarshalJSON(b []byte) error {
+	if emptyJSONArray(b) {
+		*p = GuildRaidProgression{All: map[string]RaidProgression{}}
+		return nil
+	}
+
+	// named has the fields of GuildRaidProgression without this method
+	type named GuildRaidProgression
+	var n named
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+
+	var all map[string]RaidProgression
+	if err := json.Unmarshal(b, &all); err != nil {
+		return err
+	}
+
+	*p = GuildRaidProgression(n)
+	p.All = all
+	return nil
+}
+
+// CompletedRaids returns the sorted slugs of the raids the guild has
+// fully cleared at difficulty d, across every expansion the api returned
+// The guild must be requested with RaidProgression
+func (g *Guild) CompletedRaids(d RaidDifficulty) []string {
+	var raids []string
+	for slug, rp := range g.RaidProgression.bySlug() {
+		var kills int
+		switch d {
+		case Difficulty.NormalRaid:
+			kills = rp.NormalKills
+		case Difficulty.HeroicRaid:
+			kills = rp.HeroicKills
+		case Difficulty.MythicRaid:
+			kills = rp.MythicKills
+		default:
+			return nil
+		}
+
+		if rp.Bosses > 0 && kills >= rp.Bosses {
+			raids = append(raids, slug)
+		}
+	}
+	sort.Strings(raids)
+	return raids
+}
+
+// bySlug returns the raid progression keyed by raid slug
+// Raids with an empty summary were not returned by the api and are skipped
+// All is used when set, otherwise the named raids
+func (p *GuildRaidProgression) bySlug() map[string]RaidProgression {
+	progression := map[string]RaidProgression{}
+	if p.All != nil {
+		for slug, rp := range p.All {
+			if rp.Summary != "" {
+				progression[slug] = rp
+			}
+		}
+		return progression
+	}
+
+	raids := map[string]RaidProgression{
+		"amirdrassil-the-dreams-hope":   p.Amirdrassil,
+		"aberrus-the-shadowed-crucible": p.Aberrus,
+		"vault-of-the-incarnates":       p.VaultOfTheIncarnates,
+	}
+	for slug, rp := range raids {
+		if rp.Summary != "" {
+			progression[slug] = rp
+		}
+	}
+	return progression
+}
+
+// GuildDiff is a struct that represents the differences in raid
+// progression and raid rankings between two guilds, keyed by raid slug
+// Raids which only one guild has data for are included but marked unavailable
+type GuildDiff struct {
+	Progression map[string]ProgressionDiff
+	Rankings    map[string]RankingDiff
+}
+
+// ProgressionDiff contains the difference in bosses killed per difficulty
+// Positive values mean the first guild has killed more bosses
+type ProgressionDiff struct {
+	Available bool
+	Normal    int
+	Heroic    int
+	Mythic    int
+}
+
+// RankingDiff contains the difference in raid rankings per difficulty
+type RankingDiff struct {
+	Normal RankDiff
+	Heroic RankDiff
+	Mythic RankDiff
+}
+
+// RankDiff contains the difference in world, region and realm rank
+// Positive values mean the first guild is ranked higher (a lower rank)
+// Unavailable when either guild is unranked at that difficulty
+type RankDiff struct {
+	Available bool
+	World     int
+	Region    int
+	Realm     int
+}
+
+// CompareGuilds compares the raid progression and raid rankings of two guilds
+// Both guilds should be requested with RaidProgression and RaidRankings,
+// fields which were not requested are reported as unavailable, not zero
+// A nil guild is compared as a guild without data, so every raid of the
+// other guild is reported as unavailable
+func CompareGuilds(a, b *Guild) GuildDiff {
+	diff := GuildDiff{
+		Progression: map[string]ProgressionDiff{},
+		Rankings:    map[string]RankingDiff{},
+	}
+
+	if a == nil {
+		a = &Guild{}
+	}
+	if b == nil {
+		b = &Guild{}
+	}
+
+	ap, bp := a.RaidProgression.bySlug(), b.RaidProgression.bySlug()
+	raids := map[string]bool{}
+	for slug := range ap {
+		raids[slug] = true
+	}
+	for slug := range bp {
+		raids[slug] = true
+	}
+
+	for slug := range raids {
+		pa, okA := ap[slug]
+		pb, okB := bp[slug]
+		if !okA || !okB {
+			diff.Progression[slug] = ProgressionDiff{}
+			continue
+		}
+
+		diff.Progression[slug] = ProgressionDiff{
+			Available: true,
+			Normal:    pa.NormalKills - pb.NormalKills,
+			Heroic:    pa.HeroicKills - pb.HeroicKills,
+			Mythic:    pa.MythicKills - pb.MythicKills,
+		}
 	}
 
-	if gq.Realm == "" {
-		return ErrInvalidRealm
+	raids = map[string]bool{}
+	for slug := range a.RaidRankings {
+		raids[slug] = true
+	}
+	for slug := range b.RaidRankings {
+		raids[slug] = true
+	}
+
+	for slug := range raids {
+		ra, okA := a.RaidRankings[slug]
+		rb, okB := b.RaidRankings[slug]
+		if !okA || !okB {
+			diff.Rankings[slug] = RankingDiff{}
+			continue
+		}
+
+		diff.Rankings[slug] = RankingDiff{
+			Normal: rankDiff(ra.Normal.World, ra.Normal.Region, ra.Normal.Realm,
+				rb.Normal.World, rb.Normal.Region, rb.Normal.Realm),
+			Heroic: rankDiff(ra.Heroic.World, ra.Heroic.Region, ra.Heroic.Realm,
+				rb.Heroic.World, rb.Heroic.Region, rb.Heroic.Realm),
+			Mythic: rankDiff(ra.Mythic.World, ra.Mythic.Region, ra.Mythic.Realm,
+				rb.Mythic.World, rb.Mythic.Region, rb.Mythic.Realm),
+		}
+	}
+
+	return diff
+}
+
+// rankDiff compares two sets of ranks, a zero world rank means unranked
+func rankDiff(aWorld, aRegion, aRealm, bWorld, bRegion, bRealm int) RankDiff {
+	if aWorld == 0 || bWorld == 0 {
+		return RankDiff{}
+	}
+
+	return RankDiff{
+		Available: true,
+		World:     bWorld - aWorld,
+		Region:    bRegion - aRegion,
+		Realm:     bRealm - aRealm,
+	}
+}
+
+// validate validates the GuildQuery and builds its requested fields
+// It returns an error if any of the required parameters are empty
+// or if the fields are invalid
+func (gq *GuildQuery) validate() error {
+	err := validateRegionRealm(gq.Region, gq.Realm)
+	if err != nil {
+		return err
 	}
 
 	if gq.Name == "" {
 		return ErrInvalidGuildName
 	}
 
+	// rebuilt on every validation, so a reused query doesn't repeat fields
+	gq.fields = nil
 	if gq.Members {
 		gq.fields = append(gq.fields, "members")
 	}
@@ -75,9 +335,29 @@ func createGuildQuery(gq *GuildQuery) error {
 	if gq.RaidRankings {
 		gq.fields = append(gq.fields, "raid_rankings")
 	}
+
+	if err := validateRawFields(gq.RawFields); err != nil {
+		return err
+	}
+
+	gq.fields = appendRawFields(gq.fields, gq.RawFields)
 	return nil
 }
 
+// RealmName returns the display name of the guild's realm
+// Falls back to a title cased realm slug when the display name is unknown
+func (g *Guild) RealmName() string {
+	return realmDisplayName(g.Realm)
+}
+
+// RegionObject returns the guild's region, resolved from its region slug,
+// for building further queries, ex: a GuildQuery for another guild
+// Returns false if the region is missing or not a known region
+func (g *Guild) RegionObject() (*Region, bool) {
+	r := RegionBySlug(g.Region)
+	return r, r != nil
+}
+
 func (g *Guild) GetGuildRaidRankBySlug(slug string) (*GuildRaidRanking, error) {
 	if g.RaidRankings == nil {
 		return nil, errors.New("guild raid rankings " + ErrFieldMissing.Error())
@@ -91,11 +371,44 @@ func (g *Guild) GetGuildRaidRankBySlug(slug string) (*GuildRaidRanking, error) {
 	return &gr, nil
 }
 
-func unmarshalGuild(body []byte) (*Guild, error) {
+// GuildRaidRankBySlugDifficulty returns the guild's world, region and realm
+// ranks for a raid at a single difficulty
+// Returns the same errors as GetGuildRaidRankBySlug, or ErrInvalidRaidDiff
+// if the difficulty is invalid
+func (g *Guild) GuildRaidRankBySlugDifficulty(slug string, d RaidDifficulty) (worldRank, regionRank, realmRank int, err error) {
+	gr, err := g.GetGuildRaidRankBySlug(slug)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	switch d {
+	case Difficulty.NormalRaid:
+		return gr.Normal.World, gr.Normal.Region, gr.Normal.Realm, nil
+	case Difficulty.HeroicRaid:
+		return gr.Heroic.World, gr.Heroic.Region, gr.Heroic.Realm, nil
+	case Difficulty.MythicRaid:
+		return gr.Mythic.World, gr.Mythic.Region, gr.Mythic.Realm, nil
+	}
+
+	return 0, 0, 0, ErrInvalidRaidDiff
+}
+
+func unmarshalGuild(body []byte, strict bool) (*Guild, error) {
 	var profile Guild
-	err := json.Unmarshal(body, &profile)
+	err := unmarshalJSON(body, &profile, strict, "error unmarshalling guild profile")
 	if err != nil {
-		return nil, errors.New("error unmarshalling guild profile")
+		return nil, err
+	}
+
+	for i := range profile.Members {
+		profile.Members[i].resolve()
+	}
+	profile.MemberCount = len(profile.Members)
+
+	// members share the guild's region, so it can be recovered from them
+	// when the profile omits it
+	if profile.Region == "" && len(profile.Members) > 0 {
+		profile.Region = profile.Members[0].Region
 	}
 
 	for k := range profile.RaidRankings {
@@ -106,3 +419,61 @@ func unmarshalGuild(body []byte) (*Guild, error) {
 	}
 	return &profile, nil
 }
+
+// decodeGuildMembers walks a guild profile response, decoding each entry
+// of the members array and passing it to fn. Other fields are skipped
+func decodeGuildMembers(r io.Reader, fn func(GuildMember) error) error {
+	d := json.NewDecoder(r)
+	errDecode := errors.New("error unmarshalling guild members")
+
+	t, err := d.Token()
+	if err != nil || t != json.Delim('{') {
+		return errDecode
+	}
+
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return errDecode
+		}
+
+		if t != "members" {
+			var skip json.RawMessage
+			if err := d.Decode(&skip); err != nil {
+				return errDecode
+			}
+			continue
+		}
+
+		t, err = d.Token()
+		if err != nil {
+			return errDecode
+		}
+
+		if t == nil {
+			continue
+		}
+
+		if t != json.Delim('[') {
+			return errDecode
+		}
+
+		for d.More() {
+			var m GuildMember
+			if err := d.Decode(&m); err != nil {
+				return errDecode
+			}
+			m.resolve()
+
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+
+		if _, err := d.Token(); err != nil {
+			return errDecode
+		}
+	}
+
+	return nil
+}
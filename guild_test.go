@@ -1,6 +1,11 @@
 package raiderio_test
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/tmaffia/raiderio"
@@ -41,3 +46,380 @@ func TestGetGuildRaidRankBySlug(t *testing.T) {
 		}
 	}
 }
+
+func TestProgressedDifficulties(t *testing.T) {
+	heroic := raiderio.GuildRaidRanking{}
+	heroic.Normal.World = 2000
+	heroic.Heroic.Realm = 12
+
+	mythic := heroic
+	mythic.Mythic.World = 158
+
+	testCases := []struct {
+		rank                 raiderio.GuildRaidRanking
+		expectedDifficulties []raiderio.RaidDifficulty
+	}{
+		{rank: heroic, expectedDifficulties: []raiderio.RaidDifficulty{raiderio.Difficulty.NormalRaid, raiderio.Difficulty.HeroicRaid}},
+		{rank: mythic, expectedDifficulties: []raiderio.RaidDifficulty{raiderio.Difficulty.NormalRaid,
+			raiderio.Difficulty.HeroicRaid, raiderio.Difficulty.MythicRaid}},
+		{rank: raiderio.GuildRaidRanking{}, expectedDifficulties: []raiderio.RaidDifficulty{}},
+	}
+
+	for _, tc := range testCases {
+		difficulties := tc.rank.ProgressedDifficulties()
+		if len(difficulties) != len(tc.expectedDifficulties) {
+			t.Fatalf("expected difficulties: %v, got: %v", tc.expectedDifficulties, difficulties)
+		}
+
+		for i := range difficulties {
+			if difficulties[i] != tc.expectedDifficulties[i] {
+				t.Fatalf("expected difficulties: %v, got: %v", tc.expectedDifficulties, difficulties)
+			}
+		}
+	}
+}
+
+func TestGuildRaidRankBySlugDifficulty(t *testing.T) {
+	rank := raiderio.GuildRaidRanking{}
+	rank.Mythic.World = 158
+	rank.Mythic.Region = 80
+	rank.Mythic.Realm = 3
+	rank.Heroic.World = 900
+
+	guild := raiderio.Guild{
+		RaidRankings: map[string]raiderio.GuildRaidRanking{"aberrus-the-shadowed-crucible": rank},
+	}
+
+	testCases := []struct {
+		guild          raiderio.Guild
+		raidSlug       string
+		difficulty     raiderio.RaidDifficulty
+		expectedRanks  [3]int
+		expectedErrMsg string
+	}{
+		{guild: guild, raidSlug: "aberrus-the-shadowed-crucible", difficulty: raiderio.Difficulty.MythicRaid, expectedRanks: [3]int{158, 80, 3}},
+		{guild: guild, raidSlug: "aberrus-the-shadowed-crucible", difficulty: raiderio.Difficulty.HeroicRaid, expectedRanks: [3]int{900, 0, 0}},
+		{guild: guild, raidSlug: "aberrus-the-shadowed-crucible", difficulty: "invalid", expectedErrMsg: "invalid raid difficulty"},
+		{guild: guild, raidSlug: "invalid raid slug", difficulty: raiderio.Difficulty.MythicRaid, expectedErrMsg: "invalid raid"},
+		{guild: raiderio.Guild{}, raidSlug: "aberrus-the-shadowed-crucible", difficulty: raiderio.Difficulty.MythicRaid,
+			expectedErrMsg: "guild raid rankings field missing from api response"},
+	}
+
+	for _, tc := range testCases {
+		world, region, realm, err := tc.guild.GuildRaidRankBySlugDifficulty(tc.raidSlug, tc.difficulty)
+		if err != nil && err.Error() != tc.expectedErrMsg {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErrMsg, err.Error())
+		}
+
+		if err == nil && [3]int{world, region, realm} != tc.expectedRanks {
+			t.Fatalf("expected ranks: %v, got: %v", tc.expectedRanks, [3]int{world, region, realm})
+		}
+	}
+}
+
+func TestCompareGuilds(t *testing.T) {
+	a := raiderio.Guild{
+		RaidProgression: raiderio.GuildRaidProgression{
+			Aberrus: raiderio.RaidProgression{Summary: "7/9 M", HeroicKills: 9, MythicKills: 7},
+		},
+		RaidRankings: map[string]raiderio.GuildRaidRanking{},
+	}
+	b := raiderio.Guild{
+		RaidProgression: raiderio.GuildRaidProgression{
+			Aberrus:              raiderio.RaidProgression{Summary: "9/9 M", HeroicKills: 9, MythicKills: 9},
+			VaultOfTheIncarnates: raiderio.RaidProgression{Summary: "8/8 M", MythicKills: 8},
+		},
+		RaidRankings: map[string]raiderio.GuildRaidRanking{},
+	}
+
+	rankA := raiderio.GuildRaidRanking{}
+	rankA.Heroic.World, rankA.Heroic.Region, rankA.Heroic.Realm = 100, 50, 2
+	rankA.Mythic.World = 500
+	rankB := raiderio.GuildRaidRanking{}
+	rankB.Heroic.World, rankB.Heroic.Region, rankB.Heroic.Realm = 150, 70, 3
+	a.RaidRankings["aberrus-the-shadowed-crucible"] = rankA
+	b.RaidRankings["aberrus-the-shadowed-crucible"] = rankB
+
+	diff := raiderio.CompareGuilds(&a, &b)
+
+	p := diff.Progression["aberrus-the-shadowed-crucible"]
+	if !p.Available || p.Mythic != -2 || p.Heroic != 0 {
+		t.Fatalf("unexpected aberrus progression diff: %+v", p)
+	}
+
+	if diff.Progression["vault-of-the-incarnates"].Available {
+		t.Fatalf("expected vault progression diff to be unavailable")
+	}
+
+	r := diff.Rankings["aberrus-the-shadowed-crucible"]
+	if !r.Heroic.Available || r.Heroic.World != 50 || r.Heroic.Region != 20 || r.Heroic.Realm != 1 {
+		t.Fatalf("unexpected aberrus heroic rank diff: %+v", r.Heroic)
+	}
+
+	if r.Mythic.Available || r.Normal.Available {
+		t.Fatalf("expected unranked difficulties to be unavailable, got: %+v", r)
+	}
+
+	for _, diff := range []raiderio.GuildDiff{raiderio.CompareGuilds(nil, &b), raiderio.CompareGuilds(&a, nil)} {
+		if len(diff.Progression) == 0 || len(diff.Rankings) != 1 {
+			t.Fatalf("expected diff entries for the non nil guild, got: %+v", diff)
+		}
+
+		for slug, p := range diff.Progression {
+			if p.Available {
+				t.Fatalf("expected %v progression diff to be unavailable", slug)
+			}
+		}
+
+		for slug, r := range diff.Rankings {
+			if r.Normal.Available || r.Heroic.Available || r.Mythic.Available {
+				t.Fatalf("expected %v rank diff to be unavailable, got: %+v", slug, r)
+			}
+		}
+	}
+}
+
+func TestGetGuildMembersStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Warpath","realm":"Illidan","members":[
+			{"rank":0,"character":{"name":"Highervalue","class":"Mage"}},
+			{"rank":1,"character":{"name":"Drbananaphd","class":"Priest"}},
+			{"rank":4,"character":{"name":"Thirdmember","class":"Rogue"}}
+		],"profile_url":"https://raider.io/guilds/us/illidan/Warpath"}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	gq := &raiderio.GuildQuery{Region: raiderio.Regions.US, Realm: "illidan", Name: "warpath"}
+
+	var names []string
+	err := client.GetGuildMembersStream(defaultCtx, gq, func(m raiderio.GuildMember) error {
+		names = append(names, m.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(names) != 3 || names[0] != "Highervalue" || names[2] != "Thirdmember" {
+		t.Fatalf("unexpected members: %v", names)
+	}
+
+	errStop := errors.New("stop")
+	count := 0
+	err = client.GetGuildMembersStream(defaultCtx, gq, func(m raiderio.GuildMember) error {
+		count++
+		return errStop
+	})
+	if err != errStop || count != 1 {
+		t.Fatalf("expected stream to stop after first member, got error: %v, count: %d", err, count)
+	}
+}
+
+func TestGuildMembersResolved(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Warpath","members":[{"rank":2,"character":{"name":"Highervalue",
+			"class":"Mage","level":80,"realm":"Illidan","region":"us"}}]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	profile, err := client.GetGuild(defaultCtx, &raiderio.GuildQuery{
+		Region:  raiderio.Regions.US,
+		Realm:   "illidan",
+		Name:    "warpath",
+		Members: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := raiderio.GuildMember{Rank: 2, Name: "Highervalue", Class: "Mage", Level: 80, Realm: "Illidan", Region: "us"}
+	m := profile.Members[0]
+	if m.Rank != expected.Rank || m.Name != expected.Name || m.Class != expected.Class ||
+		m.Level != expected.Level || m.Realm != expected.Realm || m.Region != expected.Region {
+		t.Fatalf("expected member: %+v, got: %+v", expected, m)
+	}
+}
+
+func TestGuildSortedMembers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Warpath","members":[
+			{"rank":3,"character":{"name":"Zuljin","realm":"Illidan","region":"us"}},
+			{"rank":1,"character":{"name":"Thrall","realm":"Illidan","region":"us"}},
+			{"rank":3,"character":{"name":"Anduin"}},
+			{"rank":0,"character":{"name":"Highervalue","class":"Mage","realm":"Illidan","region":"us"}},
+			{"rank":1,"character":{"name":"Thrall","realm":"Illidan","region":"us"}}]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	profile, err := client.GetGuild(defaultCtx, &raiderio.GuildQuery{
+		Region:  raiderio.Regions.US,
+		Realm:   "illidan",
+		Name:    "warpath",
+		Members: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(profile.Members) != 5 || profile.MemberCount != 5 {
+		t.Fatalf("expected members: %v, got: %v, count: %v", 5, len(profile.Members), profile.MemberCount)
+	}
+
+	expected := []string{"Highervalue", "Thrall", "Anduin", "Zuljin"}
+	sorted := profile.SortedMembers()
+	if len(sorted) != len(expected) {
+		t.Fatalf("expected sorted members: %v, got: %+v", expected, sorted)
+	}
+
+	for i := range expected {
+		if sorted[i].Name != expected[i] {
+			t.Fatalf("expected member: %v, got: %v", expected[i], sorted[i].Name)
+		}
+	}
+}
+
+func TestGuildCompletedRaids(t *testing.T) {
+	var guild raiderio.Guild
+	err := json.Unmarshal([]byte(`{"name":"Liquid","raid_progression":{
+		"liberation-of-undermine":{"summary":"8/8 M","total_bosses":8,"normal_bosses_killed":8,"heroic_bosses_killed":8,"mythic_bosses_killed":8},
+		"nerubar-palace":{"summary":"8/8 H","total_bosses":8,"normal_bosses_killed":5,"heroic_bosses_killed":8,"mythic_bosses_killed":0},
+		"aberrus-the-shadowed-crucible":{"summary":"9/9 M","total_bosses":9,"normal_bosses_killed":9,"heroic_bosses_killed":9,"mythic_bosses_killed":9},
+		"castle-nathria":{"summary":"3/10 N","total_bosses":10,"normal_bosses_killed":3}}}`), &guild)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(guild.RaidProgression.All) != 4 || guild.RaidProgression.Aberrus.Summary != "9/9 M" {
+		t.Fatalf("expected all raids and named raids decoded, got: %+v", guild.RaidProgression)
+	}
+
+	testCases := []struct {
+		difficulty    raiderio.RaidDifficulty
+		expectedRaids []string
+	}{
+		{difficulty: raiderio.Difficulty.MythicRaid, expectedRaids: []string{"aberrus-the-shadowed-crucible", "liberation-of-undermine"}},
+		{difficulty: raiderio.Difficulty.HeroicRaid, expectedRaids: []string{"aberrus-the-shadowed-crucible", "liberation-of-undermine", "nerubar-palace"}},
+		{difficulty: raiderio.Difficulty.NormalRaid, expectedRaids: []string{"aberrus-the-shadowed-crucible", "liberation-of-undermine"}},
+		{difficulty: raiderio.RaidDifficulty("lfr"), expectedRaids: nil},
+	}
+
+	for _, tc := range testCases {
+		raids := guild.CompletedRaids(tc.difficulty)
+		if strings.Join(raids, ",") != strings.Join(tc.expectedRaids, ",") {
+			t.Fatalf("expected %v completed raids: %v, got: %v", tc.difficulty, tc.expectedRaids, raids)
+		}
+	}
+}
+
+func TestGuildEmptyArrayFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Warpath","raid_progression":[],"raid_rankings":[ ]}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		strict bool
+	}{
+		{strict: false},
+		{strict: true},
+	}
+
+	for _, tc := range testCases {
+		var opts []raiderio.ClientOption
+		if tc.strict {
+			opts = append(opts, raiderio.WithStrictDecoding())
+		}
+		client := raiderio.NewClient(opts...)
+		client.ApiUrl = ts.URL
+
+		guild, err := client.GetGuild(defaultCtx, &raiderio.GuildQuery{
+			Region:          raiderio.Regions.US,
+			Realm:           "illidan",
+			Name:            "warpath",
+			RaidProgression: true,
+			RaidRankings:    true,
+		})
+		if err != nil {
+			t.Fatalf("strict: %v unexpected error: %v", tc.strict, err)
+		}
+
+		if len(guild.RaidProgression.All) != 0 || len(guild.RaidRankings) != 0 || guild.RaidRankings == nil {
+			t.Fatalf("strict: %v expected no raids, got: %+v %+v", tc.strict, guild.RaidProgression, guild.RaidRankings)
+		}
+
+		if len(guild.CompletedRaids(raiderio.Difficulty.MythicRaid)) != 0 {
+			t.Fatalf("expected no completed raids, got: %v", guild.CompletedRaids(raiderio.Difficulty.MythicRaid))
+		}
+	}
+}
+
+func TestGetGuildEncodedName(t *testing.T) {
+	var rawQuery string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"name":"` + r.URL.Query().Get("name") + `"}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	guild, err := client.GetGuild(defaultCtx, &raiderio.GuildQuery{
+		Region: raiderio.Regions.EU,
+		Realm:  "blackmoore",
+		Name:   "Größe & Ruhm",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "region=eu&realm=blackmoore&name=Gr%C3%B6%C3%9Fe+%26+Ruhm"
+	if rawQuery != expected {
+		t.Fatalf("expected query: %v, got: %v", expected, rawQuery)
+	}
+
+	if guild.Name != "Größe & Ruhm" {
+		t.Fatalf("expected guild name: %v, got: %v", "Größe & Ruhm", guild.Name)
+	}
+}
+
+func TestGuildRegionObject(t *testing.T) {
+	testCases := []struct {
+		response       string
+		expectedRegion *raiderio.Region
+	}{
+		{response: `{"name":"Warpath","region":"us"}`, expectedRegion: raiderio.Regions.US},
+		{response: `{"name":"Echo","region":"EU"}`, expectedRegion: raiderio.Regions.EU},
+		{response: `{"name":"Echo","members":[{"rank":0,"character":{"name":"Scripe","region":"eu"}}]}`, expectedRegion: raiderio.Regions.EU},
+		{response: `{"name":"Warpath","region":"mars"}`, expectedRegion: nil},
+		{response: `{"name":"Warpath"}`, expectedRegion: nil},
+	}
+
+	for _, tc := range testCases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tc.response))
+		}))
+
+		client := raiderio.NewClient()
+		client.ApiUrl = ts.URL
+		guild, err := client.GetGuild(defaultCtx, &raiderio.GuildQuery{
+			Region: raiderio.Regions.US,
+			Realm:  "illidan",
+			Name:   "warpath",
+		})
+		ts.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		region, ok := guild.RegionObject()
+		if region != tc.expectedRegion || ok != (tc.expectedRegion != nil) {
+			t.Fatalf("expected region: %v, got: %v %v", tc.expectedRegion, region, ok)
+		}
+	}
+}
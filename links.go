@@ -0,0 +1,41 @@
+package raiderio
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Base URL of the Raider.IO website, used to link to its pages
+const webUrl string = "https://raider.io"
+
+// CharacterURL returns the Raider.IO web page of a character, ex:
+// https://raider.io/characters/us/illidan/Highervalue
+// The realm may be a display name, it is converted to its slug with
+// NormalizeRealm. Each part is path escaped, so names with special
+// characters link correctly
+func CharacterURL(region, realm, name string) string {
+	return webURL("characters", region, NormalizeRealm(RegionBySlug(region), realm), name)
+}
+
+// GuildURL returns the Raider.IO web page of a guild, ex:
+// https://raider.io/guilds/us/illidan/Warpath
+// The realm may be a display name, it is converted to its slug with
+// NormalizeRealm. Each part is path escaped, so names with spaces link
+// correctly
+func GuildURL(region, realm, name string) string {
+	return webURL("guilds", region, NormalizeRealm(RegionBySlug(region), realm), name)
+}
+
+// RaidURL returns the Raider.IO raid rankings web page of a raid, ex:
+// https://raider.io/raid-rankings/nerubar-palace
+func RaidURL(slug string) string {
+	return webURL("raid-rankings", slug)
+}
+
+// webURL joins path escaped segments onto the website's base url
+func webURL(segments ...string) string {
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return webUrl + "/" + strings.Join(segments, "/")
+}
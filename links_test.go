@@ -0,0 +1,37 @@
+package raiderio_test
+
+import (
+	"testing"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestWebURLs(t *testing.T) {
+	testCases := []struct {
+		url         string
+		expectedUrl string
+	}{
+		{url: raiderio.CharacterURL("us", "illidan", "Highervalue"),
+			expectedUrl: "https://raider.io/characters/us/illidan/Highervalue"},
+		{url: raiderio.CharacterURL("eu", "pozzo-delleternita", "Ñàmë"),
+			expectedUrl: "https://raider.io/characters/eu/pozzo-delleternita/%C3%91%C3%A0m%C3%AB"},
+		{url: raiderio.CharacterURL("us", "illidan", "a/b?c"),
+			expectedUrl: "https://raider.io/characters/us/illidan/a%2Fb%3Fc"},
+		{url: raiderio.GuildURL("us", "illidan", "Liquid Fun"),
+			expectedUrl: "https://raider.io/guilds/us/illidan/Liquid%20Fun"},
+		{url: raiderio.CharacterURL("eu", "Argent Dawn", "Highervalue"),
+			expectedUrl: "https://raider.io/characters/eu/argent-dawn/Highervalue"},
+		{url: raiderio.CharacterURL("eu", "Pozzo dell'Eternità", "Highervalue"),
+			expectedUrl: "https://raider.io/characters/eu/pozzo-delleternita/Highervalue"},
+		{url: raiderio.GuildURL("us", "Kel'Thuzad", "Liquid Fun"),
+			expectedUrl: "https://raider.io/guilds/us/kelthuzad/Liquid%20Fun"},
+		{url: raiderio.RaidURL("nerubar-palace"),
+			expectedUrl: "https://raider.io/raid-rankings/nerubar-palace"},
+	}
+
+	for _, tc := range testCases {
+		if tc.url != tc.expectedUrl {
+			t.Fatalf("expected url: %v, got: %v", tc.expectedUrl, tc.url)
+		}
+	}
+}
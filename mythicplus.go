@@ -0,0 +1,474 @@
+package raiderio
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MythicPlusRunsQuery is a struct that represents the query parameters
+// sent for a mythic plus runs leaderboard request
+// Dungeon accepts a dungeon slug, or "all" for every dungeon
+// Supports optional request fields: season, affixes, page
+type MythicPlusRunsQuery struct {
+	Season  string
+	Region  *Region
+	Dungeon string
+	Affixes string
+	Page    int
+}
+
+// MythicPlusRuns is a struct that represents the response from a
+// mythic plus runs leaderboard request
+type MythicPlusRuns struct {
+	Rankings       []MythicPlusRanking
+	LeaderboardUrl string
+}
+
+// MythicPlusRanking is a struct that represents a single ranked run
+// in a mythic plus runs leaderboard response
+type MythicPlusRanking struct {
+	Rank  int
+	Score float64
+	Run   MythicPlusLeaderboardRun
+}
+
+// MythicPlusLeaderboardRun is a struct that represents the details of a
+// ranked run, including the roster of characters that completed it
+type MythicPlusLeaderboardRun struct {
+	Season          string
+	Dungeon         MythicPlusDungeon
+	KeystoneRunId   int64
+	MythicLevel     int
+	ClearTimeMs     int
+	KeystoneTimeMs  int
+	CompletedAt     string
+	NumChests       int
+	TimeRemainingMs int
+	WeeklyModifiers []Affix
+	Roster          []Character
+}
+
+// MythicPlusRun is a struct that represents a single mythic plus run
+// in a character profile response
+type MythicPlusRun struct {
+	Dungeon             string  `json:"dungeon"`
+	ShortName           string  `json:"short_name"`
+	MythicLevel         int     `json:"mythic_level"`
+	CompletedAt         string  `json:"completed_at"`
+	ClearTimeMs         int     `json:"clear_time_ms"`
+	ParTimeMs           int     `json:"par_time_ms"`
+	NumKeystoneUpgrades int     `json:"num_keystone_upgrades"`
+	MapChallengeModeId  int     `json:"map_challenge_mode_id"`
+	ZoneId              int     `json:"zone_id"`
+	Score               float64 `json:"score"`
+	Url                 string  `json:"url"`
+
+	// Affixes are the affixes active for the run. Older runs may only
+	// report each affix's id and name, leaving the other fields empty
+	Affixes []Affix `json:"affixes"`
+}
+
+// HasAffix reports whether an affix was active for the run, matched by
+// name case insensitively, ex: "Tyrannical"
+func (r MythicPlusRun) HasAffix(name string) bool {
+	for _, a := range r.Affixes {
+		if strings.EqualFold(a.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MythicPlusSeasonScores is a struct that represents a character's
+// mythic plus scores for a single season
+type MythicPlusSeasonScores struct {
+	Season   string                            `json:"season"`
+	Scores   MythicPlusScores                  `json:"scores"`
+	Segments map[string]MythicPlusScoreSegment `json:"segments"`
+}
+
+// MythicPlusScoreSegment is a struct that represents a single score of a
+// character's season scores, with the color Raider.IO shows the score in
+// Segments are keyed the same as the scores, ex: "all", "tank", "spec_0"
+type MythicPlusScoreSegment struct {
+	Score float64 `json:"score"`
+	Color string  `json:"color"`
+}
+
+// Color returns the hex color of the character's overall score, ex:
+// "#ff8000", or an empty string if the api did not report one
+func (s MythicPlusSeasonScores) Color() string {
+	return s.Segments["all"].Color
+}
+
+// SeasonScore returns the character's overall score for a season, by the
+// season slug the api returned, ex: "season-tww-2"
+// Returns false if the season was not requested, or the character did not
+// play it. The character must be requested with MythicPlusScores
+func (c *Character) SeasonScore(season string) (float64, bool) {
+	for _, s := range c.MythicPlusScoresBySeason {
+		if s.Season == season && s.Scores.All > 0 {
+			return s.Scores.All, true
+		}
+	}
+	return 0, false
+}
+
+// AllSeasonScores returns the character's overall score for every season
+// returned by the api, keyed by season slug
+// Seasons the character did not play are left out
+func (c *Character) AllSeasonScores() map[string]float64 {
+	scores := map[string]float64{}
+	for _, s := range c.MythicPlusScoresBySeason {
+		if s.Scores.All > 0 {
+			scores[s.Season] = s.Scores.All
+		}
+	}
+	return scores
+}
+
+// MythicPlusScores is a struct that represents a character's mythic plus
+// scores, overall, per role and per spec. Spec0 to Spec3 follow the order
+// of the class's specs in game, see BySpec. Specs with no runs score zero
+type MythicPlusScores struct {
+	All    float64 `json:"all"`
+	DPS    float64 `json:"dps"`
+	Healer float64 `json:"healer"`
+	Tank   float64 `json:"tank"`
+	Spec0  float64 `json:"spec_0"`
+	Spec1  float64 `json:"spec_1"`
+	Spec2  float64 `json:"spec_2"`
+	Spec3  float64 `json:"spec_3"`
+}
+
+// Overall returns the character's overall mythic plus score
+func (s MythicPlusScores) Overall() float64 {
+	return s.All
+}
+
+// classSpecs lists each class's spec slugs in the order the api
+// reports their scores, spec_0 to spec_3
+var classSpecs = map[string][]string{
+	"death-knight": {"blood", "frost", "unholy"},
+	"demon-hunter": {"havoc", "vengeance"},
+	"druid":        {"balance", "feral", "guardian", "restoration"},
+	"evoker":       {"devastation", "preservation", "augmentation"},
+	"hunter":       {"beast-mastery", "marksmanship", "survival"},
+	"mage":         {"arcane", "fire", "frost"},
+	"monk":         {"brewmaster", "windwalker", "mistweaver"},
+	"paladin":      {"holy", "protection", "retribution"},
+	"priest":       {"discipline", "holy", "shadow"},
+	"rogue":        {"assassination", "outlaw", "subtlety"},
+	"shaman":       {"elemental", "enhancement", "restoration"},
+	"warlock":      {"affliction", "demonology", "destruction"},
+	"warrior":      {"arms", "fury", "protection"},
+}
+
+// BySpec returns the spec scores keyed by spec slug, for the given class
+// Specs with a score of zero are omitted
+// Returns an empty map if the class is not recognized
+func (s MythicPlusScores) BySpec(class string) map[string]float64 {
+	scores := map[string]float64{}
+	bySpecIndex := []float64{s.Spec0, s.Spec1, s.Spec2, s.Spec3}
+	for i, spec := range classSpecs[toSlug(class)] {
+		if bySpecIndex[i] != 0 {
+			scores[spec] = bySpecIndex[i]
+		}
+	}
+	return scores
+}
+
+// MythicPlusStaticData is a struct that represents the response from a
+// mythic plus static data request, the seasons and dungeons of an expansion
+type MythicPlusStaticData struct {
+	Seasons  []MythicPlusSeason  `json:"seasons"`
+	Dungeons []MythicPlusDungeon `json:"dungeons"`
+}
+
+// MythicPlusSeason is a struct that represents a mythic plus season in a
+// mythic plus static data response, including the season's dungeon pool
+type MythicPlusSeason struct {
+	Slug      string `json:"slug"`
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	Starts    struct {
+		Us string `json:"us"`
+		Eu string `json:"eu"`
+		Tw string `json:"tw"`
+		Kr string `json:"kr"`
+		Cn string `json:"cn"`
+	} `json:"starts"`
+	Ends struct {
+		Us string `json:"us"`
+		Eu string `json:"eu"`
+		Tw string `json:"tw"`
+		Kr string `json:"kr"`
+		Cn string `json:"cn"`
+	} `json:"ends"`
+
+	Dungeons []MythicPlusDungeon `json:"dungeons"`
+}
+
+// start returns the earliest start of the season in any region
+// Returns false for seasons with no start date, ex: an announced season
+func (s *MythicPlusSeason) start() (time.Time, bool) {
+	var start time.Time
+	for _, starts := range []string{s.Starts.Us, s.Starts.Eu, s.Starts.Tw, s.Starts.Kr, s.Starts.Cn} {
+		t, err := time.Parse(time.RFC3339, starts)
+		if err == nil && (start.IsZero() || t.Before(start)) {
+			start = t
+		}
+	}
+	return start, !start.IsZero()
+}
+
+// resolveSeason finds a season in the seasons returned by the api
+// An empty season or "current" is the most recently started season as of
+// now, and "previous" the one started before it, so a new season is picked
+// up as soon as the api lists it. Returns ErrInvalidSeason if there is no
+// such season
+func resolveSeason(seasons []MythicPlusSeason, season string, now time.Time) (*MythicPlusSeason, error) {
+	back := 0
+	switch season {
+	case "", CurrentSeasonSlug:
+	case PreviousSeasonSlug:
+		back = 1
+	default:
+		for i := range seasons {
+			if seasons[i].Slug == season {
+				return &seasons[i], nil
+			}
+		}
+		return nil, ErrInvalidSeason
+	}
+
+	var started []*MythicPlusSeason
+	for i := range seasons {
+		if start, ok := seasons[i].start(); ok && !now.Before(start) {
+			started = append(started, &seasons[i])
+		}
+	}
+
+	sort.SliceStable(started, func(i, j int) bool {
+		a, _ := started[i].start()
+		b, _ := started[j].start()
+		return a.After(b)
+	})
+
+	if len(started) <= back {
+		return nil, ErrInvalidSeason
+	}
+	return started[back], nil
+}
+
+// MythicPlusDungeon is a struct that represents a mythic plus dungeon
+type MythicPlusDungeon struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	Slug      string `json:"slug"`
+}
+
+// Affix is a struct that represents a mythic plus affix
+type Affix struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	WowheadUrl  string `json:"wowhead_url"`
+}
+
+// MythicPlusAffixes is a struct that represents the response from
+// a mythic plus affixes request, the affixes active this week in a region
+type MythicPlusAffixes struct {
+	Region         string  `json:"region"`
+	Title          string  `json:"title"`
+	LeaderboardUrl string  `json:"leaderboard_url"`
+	AffixDetails   []Affix `json:"affix_details"`
+}
+
+// AffixRotation lists the weekly affix sets of the current season, in
+// rotation order. The api only reports the current week's affixes, so
+// AffixesForWeek uses the rotation to work out past and future weeks
+// The rotation is The War Within season 2's, as listed on Raider.IO's
+// weekly affixes page, and must be updated when a new season starts
+var AffixRotation = [][]string{
+	{"Xal'atath's Bargain: Ascendant", "Tyrannical", "Fortified", "Xal'atath's Guile"},
+	{"Xal'atath's Bargain: Voidbound", "Fortified", "Tyrannical", "Xal'atath's Guile"},
+	{"Xal'atath's Bargain: Pulsar", "Tyrannical", "Fortified", "Xal'atath's Guile"},
+	{"Xal'atath's Bargain: Devour", "Fortified", "Tyrannical", "Xal'atath's Guile"},
+}
+
+// AffixesForWeek returns the names of the affixes weeks away from the
+// current week's affixes, ex: 1 for next week, -1 for last week
+// current is usually the AffixDetails of a GetMythicPlusAffixes response
+// Affixes are matched against AffixRotation regardless of order
+// Returns ErrUnknownAffixes if the current affixes are not in the rotation
+func AffixesForWeek(current []Affix, weeks int) ([]string, error) {
+	i, err := rotationIndex(current)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(AffixRotation)
+	return AffixRotation[((i+weeks)%n+n)%n], nil
+}
+
+// rotationIndex returns the index in AffixRotation of a set of affixes
+// Affixes are matched regardless of order
+func rotationIndex(affixes []Affix) (int, error) {
+	names := map[string]bool{}
+	for _, a := range affixes {
+		names[a.Name] = true
+	}
+
+	for i, set := range AffixRotation {
+		if len(set) != len(names) {
+			continue
+		}
+
+		match := true
+		for _, name := range set {
+			if !names[name] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return i, nil
+		}
+	}
+
+	return 0, ErrUnknownAffixes
+}
+
+// Names returns the names of this week's affixes, in the order returned by
+// the api, ex: for strings.Join(a.Names(), ", ")
+func (a *MythicPlusAffixes) Names() []string {
+	names := make([]string, 0, len(a.AffixDetails))
+	for _, affix := range a.AffixDetails {
+		names = append(names, affix.Name)
+	}
+	return names
+}
+
+// RotationIndex returns the index of this week's affixes in AffixRotation,
+// identifying the week of the rotation
+// Returns ErrUnknownAffixes if this week's affixes are not in the rotation
+func (a *MythicPlusAffixes) RotationIndex() (int, error) {
+	return rotationIndex(a.AffixDetails)
+}
+
+// The following structs are unexported, for use within the package
+// to convert the incoming run roster into standard "Character" types,
+// the same way the boss kill roster is converted
+type mythicPlusRunsResp struct {
+	Rankings []struct {
+		Rank  int     `json:"rank"`
+		Score float64 `json:"score"`
+		Run   struct {
+			Season          string                  `json:"season"`
+			Dungeon         MythicPlusDungeon       `json:"dungeon"`
+			KeystoneRunId   int64                   `json:"keystone_run_id"`
+			MythicLevel     int                     `json:"mythic_level"`
+			ClearTimeMs     int                     `json:"clear_time_ms"`
+			KeystoneTimeMs  int                     `json:"keystone_time_ms"`
+			CompletedAt     string                  `json:"completed_at"`
+			NumChests       int                     `json:"num_chests"`
+			TimeRemainingMs int                     `json:"time_remaining_ms"`
+			WeeklyModifiers []Affix                 `json:"weekly_modifiers"`
+			Roster          []mythicPlusRosterEntry `json:"roster"`
+		} `json:"run"`
+	} `json:"rankings"`
+	LeaderboardUrl string `json:"leaderboard_url"`
+}
+type mythicPlusRosterEntry struct {
+	Character struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Class struct {
+			Name string `json:"name"`
+		} `json:"class"`
+		Spec struct {
+			Name string `json:"name"`
+		} `json:"spec"`
+		Realm struct {
+			Slug string `json:"slug"`
+		} `json:"realm"`
+		Region struct {
+			Slug string `json:"slug"`
+		} `json:"region"`
+	} `json:"character"`
+	Role string `json:"role"`
+}
+
+// unmarshalMythicPlusRuns maps the /mythic-plus/runs response
+// into the simplified MythicPlusRuns struct
+func unmarshalMythicPlusRuns(b []byte) (*MythicPlusRuns, error) {
+	resp := mythicPlusRunsResp{}
+	err := json.Unmarshal(b, &resp)
+	if err != nil {
+		return nil, errors.New("error unmarshalling mythic plus runs")
+	}
+
+	runs := MythicPlusRuns{LeaderboardUrl: resp.LeaderboardUrl}
+	for _, r := range resp.Rankings {
+		var roster []Character
+		for _, e := range r.Run.Roster {
+			roster = append(roster, Character{
+				ID:         e.Character.ID,
+				Name:       e.Character.Name,
+				Class:      e.Character.Class.Name,
+				ActiveSpec: e.Character.Spec.Name,
+				ActiveRole: e.Role,
+				Realm:      e.Character.Realm.Slug,
+				Region:     e.Character.Region.Slug,
+			})
+		}
+
+		runs.Rankings = append(runs.Rankings, MythicPlusRanking{
+			Rank:  r.Rank,
+			Score: r.Score,
+			Run: MythicPlusLeaderboardRun{
+				Season:          r.Run.Season,
+				Dungeon:         r.Run.Dungeon,
+				KeystoneRunId:   r.Run.KeystoneRunId,
+				MythicLevel:     r.Run.MythicLevel,
+				ClearTimeMs:     r.Run.ClearTimeMs,
+				KeystoneTimeMs:  r.Run.KeystoneTimeMs,
+				CompletedAt:     r.Run.CompletedAt,
+				NumChests:       r.Run.NumChests,
+				TimeRemainingMs: r.Run.TimeRemainingMs,
+				WeeklyModifiers: r.Run.WeeklyModifiers,
+				Roster:          roster,
+			},
+		})
+	}
+	return &runs, nil
+}
+
+// validate validates a MythicPlusRunsQuery struct
+// ensures that the required parameters are not empty
+func (q *MythicPlusRunsQuery) validate() error {
+	if q.Region == nil {
+		return ErrInvalidRegion
+	}
+
+	if q.Dungeon == "" {
+		return ErrInvalidDungeon
+	}
+
+	if q.Season != "" && !seasonValid(q.Season) {
+		return ErrInvalidSeason
+	}
+
+	if q.Page < 0 {
+		return ErrPageOutOfBounds
+	}
+
+	return nil
+}
@@ -0,0 +1,320 @@
+package raiderio_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestGetMythicPlusRunsRoster(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rankings":[{"rank":1,"score":512.4,"run":{
+			"season":"season-tww-2","dungeon":{"id":1,"name":"Priory of the Sacred Flame","short_name":"PSF","slug":"priory-of-the-sacred-flame"},
+			"keystone_run_id":9007199254,"mythic_level":20,"clear_time_ms":1700000,
+			"weekly_modifiers":[{"id":10,"name":"Fortified"}],
+			"roster":[{"character":{"name":"Highervalue","class":{"name":"Mage"},"spec":{"name":"Fire"},
+				"realm":{"slug":"illidan"},"region":{"slug":"us"}},"role":"dps"}]}}]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	runs, err := client.GetMythicPlusRuns(defaultCtx, &raiderio.MythicPlusRunsQuery{
+		Region:  raiderio.Regions.WORLD,
+		Dungeon: "all",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	run := runs.Rankings[0].Run
+	if run.Dungeon.Slug != "priory-of-the-sacred-flame" || run.MythicLevel != 20 || run.KeystoneRunId != 9007199254 {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+
+	if len(run.Roster) != 1 || run.Roster[0].Name != "Highervalue" || run.Roster[0].ActiveRole != "dps" {
+		t.Fatalf("unexpected roster: %+v", run.Roster)
+	}
+
+	if len(run.WeeklyModifiers) != 1 || run.WeeklyModifiers[0].Name != "Fortified" {
+		t.Fatalf("unexpected weekly modifiers: %+v", run.WeeklyModifiers)
+	}
+}
+
+func TestGetCharacterWWeeklyRuns(t *testing.T) {
+	testCases := []struct {
+		response             string
+		expectedWeeklyRuns   int
+		expectedPreviousRuns int
+	}{
+		{response: `{"name":"Highervalue","mythic_plus_weekly_highest_level_runs":[{"dungeon":"Operation: Floodgate","mythic_level":12,"score":310.5}],
+			"mythic_plus_previous_weekly_highest_level_runs":[]}`, expectedWeeklyRuns: 1},
+		{response: `{"name":"Highervalue"}`},
+	}
+
+	for _, tc := range testCases {
+		var fields string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fields = r.URL.Query().Get("fields")
+			w.Write([]byte(tc.response))
+		}))
+
+		client := raiderio.NewClient()
+		client.ApiUrl = ts.URL
+		profile, err := client.GetCharacter(defaultCtx, &raiderio.CharacterQuery{
+			Region:                              raiderio.Regions.US,
+			Realm:                               "illidan",
+			Name:                                "highervalue",
+			MythicPlusWeeklyHighestRuns:         true,
+			MythicPlusPreviousWeeklyHighestRuns: true,
+		})
+		ts.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if fields != "mythic_plus_weekly_highest_level_runs,mythic_plus_previous_weekly_highest_level_runs" {
+			t.Fatalf("unexpected fields requested: %v", fields)
+		}
+
+		if profile.MythicPlusWeeklyHighestRuns == nil || profile.MythicPlusPreviousWeeklyHighestRuns == nil {
+			t.Fatalf("expected requested weekly runs to be non-nil")
+		}
+
+		if len(profile.MythicPlusWeeklyHighestRuns) != tc.expectedWeeklyRuns ||
+			len(profile.MythicPlusPreviousWeeklyHighestRuns) != tc.expectedPreviousRuns {
+			t.Fatalf("unexpected weekly runs: %+v, previous: %+v",
+				profile.MythicPlusWeeklyHighestRuns, profile.MythicPlusPreviousWeeklyHighestRuns)
+		}
+	}
+}
+
+func TestMythicPlusScoresBySpec(t *testing.T) {
+	testCases := []struct {
+		class          string
+		scores         raiderio.MythicPlusScores
+		expectedScores map[string]float64
+	}{
+		{class: "Demon Hunter", scores: raiderio.MythicPlusScores{All: 3100, Spec0: 2900, Spec1: 3100},
+			expectedScores: map[string]float64{"havoc": 2900, "vengeance": 3100}},
+		{class: "monk", scores: raiderio.MythicPlusScores{All: 2500, Spec2: 2500},
+			expectedScores: map[string]float64{"mistweaver": 2500}},
+		{class: "unknown", scores: raiderio.MythicPlusScores{All: 2500, Spec0: 2500},
+			expectedScores: map[string]float64{}},
+	}
+
+	for _, tc := range testCases {
+		bySpec := tc.scores.BySpec(tc.class)
+		if len(bySpec) != len(tc.expectedScores) {
+			t.Fatalf("expected scores: %v, got: %v", tc.expectedScores, bySpec)
+		}
+
+		for spec, score := range tc.expectedScores {
+			if bySpec[spec] != score {
+				t.Fatalf("expected %v score: %v, got: %v", spec, score, bySpec[spec])
+			}
+		}
+
+		if tc.scores.Overall() != tc.scores.All {
+			t.Fatalf("expected overall score: %v, got: %v", tc.scores.All, tc.scores.Overall())
+		}
+	}
+}
+
+func TestAffixesForWeek(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"region":"us","title":"Tyrannical, Xal'atath's Bargain: Pulsar, Fortified, Xal'atath's Guile",
+			"affix_details":[{"id":9,"name":"Tyrannical"},{"id":160,"name":"Xal'atath's Bargain: Pulsar"},
+			{"id":10,"name":"Fortified"},{"id":147,"name":"Xal'atath's Guile"}]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	affixes, err := client.GetMythicPlusAffixes(defaultCtx, raiderio.Regions.US)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		weeks           int
+		expectedBargain string
+	}{
+		{weeks: 0, expectedBargain: "Xal'atath's Bargain: Pulsar"},
+		{weeks: 1, expectedBargain: "Xal'atath's Bargain: Devour"},
+		{weeks: 2, expectedBargain: "Xal'atath's Bargain: Ascendant"},
+		{weeks: -1, expectedBargain: "Xal'atath's Bargain: Voidbound"},
+		{weeks: -7, expectedBargain: "Xal'atath's Bargain: Devour"},
+	}
+
+	for _, tc := range testCases {
+		names, err := raiderio.AffixesForWeek(affixes.AffixDetails, tc.weeks)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if names[0] != tc.expectedBargain {
+			t.Fatalf("expected affix %d weeks away: %v, got: %v", tc.weeks, tc.expectedBargain, names[0])
+		}
+	}
+
+	names := strings.Join(affixes.Names(), ", ")
+	if names != "Tyrannical, Xal'atath's Bargain: Pulsar, Fortified, Xal'atath's Guile" {
+		t.Fatalf("unexpected affix names: %v", names)
+	}
+
+	week, err := affixes.RotationIndex()
+	if err != nil || week != 2 {
+		t.Fatalf("expected rotation index: %v, got: %v %v", 2, week, err)
+	}
+
+	unknown := raiderio.MythicPlusAffixes{AffixDetails: []raiderio.Affix{{Name: "Bolstering"}}}
+	if _, err := unknown.RotationIndex(); err != raiderio.ErrUnknownAffixes {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrUnknownAffixes, err)
+	}
+
+	_, err = raiderio.AffixesForWeek([]raiderio.Affix{{Name: "Bolstering"}}, 1)
+	if err != raiderio.ErrUnknownAffixes {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrUnknownAffixes, err)
+	}
+
+	_, err = client.GetMythicPlusAffixes(defaultCtx, nil)
+	if err != raiderio.ErrInvalidRegion {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrInvalidRegion, err)
+	}
+}
+
+func TestGetMythicPlusStaticData(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("expansion_id") != strconv.Itoa(int(raiderio.Expansions.WarWithin)) {
+			w.Write([]byte(`{"seasons":[]}`))
+			return
+		}
+
+		// seasons are out of order, and include seasons which have not started
+		w.Write([]byte(`{"seasons":[
+			{"slug":"season-tww-4","name":"TWW Season 4","starts":{"us":null,"eu":null},"dungeons":[{"slug":"magisters-terrace"}]},
+			{"slug":"season-tww-2","name":"TWW Season 2","starts":{"us":"2025-03-04T15:00:00Z","eu":"2025-03-05T04:00:00Z"},
+				"dungeons":[{"slug":"operation-floodgate"}]},
+			{"slug":"season-tww-3","name":"TWW Season 3","starts":{"us":"2025-08-12T15:00:00Z","eu":"2025-08-13T04:00:00Z"},
+				"dungeons":[{"slug":"ecodome-aldani"},{"slug":"ara-kara-city-of-echoes"}]},
+			{"slug":"season-tww-1","name":"TWW Season 1","starts":{"us":"2024-09-17T15:00:00Z","eu":"2024-09-18T04:00:00Z"},
+				"dungeons":[{"slug":"the-stonevault"}]}
+		]}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		season          string
+		expansion       raiderio.Expansion
+		now             time.Time
+		expectedSeason  string
+		expectedDungeon string
+		expectedErr     error
+	}{
+		{season: "", expansion: raiderio.Expansions.WarWithin, expectedSeason: "season-tww-3", expectedDungeon: "ecodome-aldani"},
+		{season: "current", expansion: raiderio.Expansions.WarWithin, expectedSeason: "season-tww-3", expectedDungeon: "ecodome-aldani"},
+		{season: "previous", expansion: raiderio.Expansions.WarWithin, expectedSeason: "season-tww-2", expectedDungeon: "operation-floodgate"},
+		{season: "current", expansion: raiderio.Expansions.WarWithin, now: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+			expectedSeason: "season-tww-2", expectedDungeon: "operation-floodgate"},
+		{season: "previous", expansion: raiderio.Expansions.WarWithin, now: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+			expectedErr: raiderio.ErrInvalidSeason},
+		{season: "season-tww-1", expansion: raiderio.Expansions.WarWithin, expectedSeason: "season-tww-1", expectedDungeon: "the-stonevault"},
+		{season: "season-tww-4", expansion: raiderio.Expansions.WarWithin, expectedSeason: "season-tww-4", expectedDungeon: "magisters-terrace"},
+		{season: "season-df-1", expansion: raiderio.Expansions.WarWithin, expectedErr: raiderio.ErrInvalidSeason},
+		{season: "", expansion: raiderio.Expansions.Legion, expectedErr: raiderio.ErrInvalidSeason},
+	}
+
+	for _, tc := range testCases {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		if !tc.now.IsZero() {
+			now = tc.now
+		}
+		client := raiderio.NewClient(raiderio.WithClock(func() time.Time { return now }))
+		client.ApiUrl = ts.URL
+
+		season, err := client.GetMythicPlusStaticData(defaultCtx, tc.expansion, tc.season)
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if err == nil && (season.Slug != tc.expectedSeason || season.Dungeons[0].Slug != tc.expectedDungeon) {
+			t.Fatalf("expected season: %v dungeon: %v, got: %v dungeons: %v",
+				tc.expectedSeason, tc.expectedDungeon, season.Slug, season.Dungeons)
+		}
+	}
+}
+
+func TestMythicPlusRunAffixes(t *testing.T) {
+	var char raiderio.Character
+	err := json.Unmarshal([]byte(`{"mythic_plus_weekly_highest_level_runs":[
+		{"dungeon":"Operation: Floodgate","affixes":[{"id":9,"name":"Tyrannical","description":"Bosses have more health",
+			"icon":"achievement_boss_archaedas","wowhead_url":"https://wowhead.com/affix=9"},{"id":160,"name":"Xal'atath's Bargain: Devour"}]},
+		{"dungeon":"The Rookery","affixes":[{"id":10,"name":"Fortified"}]},
+		{"dungeon":"Priory of the Sacred Flame"}]}`), &char)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		run         raiderio.MythicPlusRun
+		affix       string
+		expectedHas bool
+	}{
+		{run: char.MythicPlusWeeklyHighestRuns[0], affix: "tyrannical", expectedHas: true},
+		{run: char.MythicPlusWeeklyHighestRuns[0], affix: "Fortified", expectedHas: false},
+		{run: char.MythicPlusWeeklyHighestRuns[1], affix: "Fortified", expectedHas: true},
+		{run: char.MythicPlusWeeklyHighestRuns[2], affix: "Fortified", expectedHas: false},
+	}
+
+	for _, tc := range testCases {
+		if tc.run.HasAffix(tc.affix) != tc.expectedHas {
+			t.Fatalf("%v expected affix %v: %v, got: %v", tc.run.Dungeon, tc.affix, tc.expectedHas, !tc.expectedHas)
+		}
+	}
+
+	if char.MythicPlusWeeklyHighestRuns[0].Affixes[0].WowheadUrl != "https://wowhead.com/affix=9" {
+		t.Fatalf("unexpected affix: %+v", char.MythicPlusWeeklyHighestRuns[0].Affixes[0])
+	}
+}
+
+func TestCharacterSeasonScores(t *testing.T) {
+	var char raiderio.Character
+	err := json.Unmarshal([]byte(`{"mythic_plus_scores_by_season":[
+		{"season":"season-tww-3","scores":{"all":3012.5}},
+		{"season":"season-tww-2","scores":{"all":2870}},
+		{"season":"season-tww-1","scores":{"all":0}}]}`), &char)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		season        string
+		expectedScore float64
+		expectedOk    bool
+	}{
+		{season: "season-tww-3", expectedScore: 3012.5, expectedOk: true},
+		{season: "season-tww-2", expectedScore: 2870, expectedOk: true},
+		{season: "season-tww-1"},
+		{season: "season-df-4"},
+	}
+
+	for _, tc := range testCases {
+		score, ok := char.SeasonScore(tc.season)
+		if score != tc.expectedScore || ok != tc.expectedOk {
+			t.Fatalf("expected %v score: %v %v, got: %v %v", tc.season, tc.expectedScore, tc.expectedOk, score, ok)
+		}
+	}
+
+	all := char.AllSeasonScores()
+	if len(all) != 2 || all["season-tww-3"] != 3012.5 || all["season-tww-2"] != 2870 {
+		t.Fatalf("unexpected season scores: %v", all)
+	}
+}
@@ -0,0 +1,234 @@
+package raiderio
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClientOption configures optional behavior of a Client
+// Options are passed to NewClient
+type ClientOption func(*Client)
+
+// WithRawResponses keeps a copy of the most recent successful response
+// body, retrievable with LastRaw()
+func WithRawResponses() ClientOption {
+	return func(c *Client) {
+		c.rawResponses = true
+	}
+}
+
+// LastRaw returns a copy of the raw body of the most recent successful
+// api response, or nil if WithRawResponses was not set
+// When the client is shared between goroutines, the body returned may
+// belong to a request made by another goroutine
+func (c *Client) LastRaw() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lastRaw == nil {
+		return nil
+	}
+
+	raw := make([]byte, len(c.lastRaw))
+	copy(raw, c.lastRaw)
+	return raw
+}
+
+// WithConnTrace reports DNS, connect, TLS and first byte timings, and
+// whether the connection was reused, to fn after every api request
+// Tracing adds overhead to each request, so it is disabled by default
+func WithConnTrace(fn func(ConnTrace)) ClientOption {
+	return func(c *Client) {
+		c.connTrace = fn
+	}
+}
+
+// WithStrictDecoding rejects api responses containing fields the library
+// does not model, returning ErrDecode. Useful for detecting api schema changes
+// Boss kill and mythic plus runs responses are intentionally simplified,
+// so they are always decoded leniently
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// WithSchemaWarnings reports fields in api responses which the library does
+// not model to fn, without failing the request. It is the non fatal
+// counterpart to WithStrictDecoding, for noticing api schema changes
+// fn receives the endpoint, ex: "characters/profile", and the dotted paths
+// of the unknown fields, ex: "gear.items.head.new_field"
+// Boss kill and mythic plus runs responses are intentionally simplified,
+// so they are not checked
+func WithSchemaWarnings(fn func(endpoint string, unknownFields []string)) ClientOption {
+	return func(c *Client) {
+		c.schemaWarnings = fn
+	}
+}
+
+// WithDefaultRegion sets the region used by queries with no Region, for
+// tools which only look at a single region
+// A region set on the query always wins
+func WithDefaultRegion(region *Region) ClientOption {
+	return func(c *Client) {
+		c.defaultRegion = region
+	}
+}
+
+// WithDefaultRealm sets the realm used by character, guild and boss kill
+// queries with no Realm, for tools which only look at a single realm
+// Raid rankings queries are not scoped to the default realm
+// A realm set on the query always wins
+func WithDefaultRealm(realm string) ClientOption {
+	return func(c *Client) {
+		c.defaultRealm = realm
+	}
+}
+
+// WithPerAttemptTimeout bounds each request sent to the api by d, in
+// addition to the deadline of the context passed to the client method
+// Batch methods such as GetGuilds share one context across many requests,
+// this keeps a single slow request from using up the whole context
+// The client does not retry requests, so each request is a single attempt
+// A timed out attempt returns ErrApiTimeout
+func WithPerAttemptTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.attemptTimeout = d
+	}
+}
+
+// WithETagCache enables conditional requests. Responses with an ETag are
+// stored in cache, and repeated requests for the same url send the ETag as
+// If-None-Match. When the api answers 304 Not Modified, the stored body is
+// used, saving bandwidth for resources which rarely change
+// If the api ignores the header, responses are handled as usual
+// See NewMemoryETagCache for an in memory cache
+func WithETagCache(cache ETagCache) ClientOption {
+	return func(c *Client) {
+		c.etagCache = cache
+	}
+}
+
+// WithDefaultParams adds params to the query of every api request, for
+// params the typed queries do not expose, ex: an analytics "source" tag
+// Params set by the endpoint always win, a default param is only sent
+// when the request does not already set it
+func WithDefaultParams(params url.Values) ClientOption {
+	return func(c *Client) {
+		c.defaultParams = url.Values{}
+		for k, vs := range params {
+			c.defaultParams[k] = append([]string(nil), vs...)
+		}
+	}
+}
+
+// Header the request id of WithRequestIDFromContext is sent in
+const requestIDHeader string = "X-Request-ID"
+
+// WithRequestIDFromContext tags api requests with a request id read from the
+// context passed to the client method, for correlating them with the
+// caller's logs. fn maps the caller's own context key to the id
+// The id is sent in the X-Request-ID header, and reported in ConnTrace
+// when WithConnTrace is set. Requests are not tagged when fn returns ""
+func WithRequestIDFromContext(fn func(context.Context) string) ClientOption {
+	return func(c *Client) {
+		c.requestID = fn
+	}
+}
+
+// WithClock sets the clock the client reads the current time from, in place
+// of time.Now, so time dependent behavior can be tested deterministically,
+// ex: the RetryAfter of a RateLimitError with an HTTP date Retry-After
+// Request timings reported by WithConnTrace always use the real clock
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// WithBaseURL sets the base URL the client sends requests to, in place of
+// https://raider.io/api. The api version is appended to it
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseUrl = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithAPIVersion sets the api version appended to the base URL,
+// in place of the default "v1"
+func WithAPIVersion(version string) ClientOption {
+	return func(c *Client) {
+		c.apiVersion = strings.Trim(version, "/")
+	}
+}
+
+// WithInsecureSkipVerify disables TLS certificate verification, for testing
+// against a local proxy with a self signed certificate, with WithBaseURL
+// FOR TESTING ONLY. It makes the client trust any server, and must never be
+// used against the real api. Verification is enabled by default
+func WithInsecureSkipVerify() ClientOption {
+	return func(c *Client) {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+		c.HttpClient.Transport = t
+	}
+}
+
+// Maximum number of redirects followed by the default redirect policy
+const maxRedirects int = 5
+
+// WithRedirectPolicy sets the policy used to follow redirects, in place of
+// the default, which follows up to 5 redirects. Errors returned by the policy
+// are returned from the request, wrapped so errors.Is matches them, ex:
+// ErrTooManyRedirects. A policy returning http.ErrUseLastResponse stops at
+// the redirect, and the request fails with ErrRedirectNotFollowed
+// See http.Client.CheckRedirect for details on the policy function
+func WithRedirectPolicy(policy func(req *http.Request, via []*http.Request) error) ClientOption {
+	return func(c *Client) {
+		if policy == nil {
+			c.HttpClient.CheckRedirect = nil
+			return
+		}
+
+		c.HttpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			err := policy(req, via)
+			if err != nil && err != http.ErrUseLastResponse {
+				return &redirectPolicyError{err: err}
+			}
+			return err
+		}
+	}
+}
+
+// LimitRedirects returns a redirect policy which follows at most max redirects
+func LimitRedirects(max int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return ErrTooManyRedirects
+		}
+		return nil
+	}
+}
+
+// SameHostRedirects returns a redirect policy which follows at most max
+// redirects, and rejects redirects to a different host than the original
+// request with ErrCrossHostRedirect
+func SameHostRedirects(max int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return ErrTooManyRedirects
+		}
+
+		if req.URL.Host != via[0].URL.Host {
+			return ErrCrossHostRedirect
+		}
+		return nil
+	}
+}
@@ -0,0 +1,680 @@
+package raiderio_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestWithRawResponses(t *testing.T) {
+	body := `{"raids":[{"slug":"nerubar-palace","name":"Nerub-ar Palace"}]}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		opts        []raiderio.ClientOption
+		expectedRaw string
+	}{
+		{opts: []raiderio.ClientOption{raiderio.WithRawResponses()}, expectedRaw: body},
+		{opts: nil, expectedRaw: ""},
+	}
+
+	for _, tc := range testCases {
+		client := raiderio.NewClient(tc.opts...)
+		client.ApiUrl = ts.URL
+
+		_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(client.LastRaw()) != tc.expectedRaw {
+			t.Fatalf("expected raw response: %v, got: %v", tc.expectedRaw, string(client.LastRaw()))
+		}
+	}
+}
+
+func TestWithConnTrace(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"raids":[]}`))
+	}))
+	defer ts.Close()
+
+	var traces []raiderio.ConnTrace
+	client := raiderio.NewClient(raiderio.WithConnTrace(func(ct raiderio.ConnTrace) {
+		traces = append(traces, ct)
+	}))
+	client.ApiUrl = ts.URL
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(traces) != 2 {
+		t.Fatalf("expected 2 traces, got: %d", len(traces))
+	}
+
+	if traces[0].Reused || !traces[1].Reused {
+		t.Fatalf("expected only the second connection to be reused, got: %+v", traces)
+	}
+
+	if traces[0].FirstByte <= 0 {
+		t.Fatalf("expected first byte timing to be recorded, got: %v", traces[0].FirstByte)
+	}
+}
+
+func TestLastRateLimit(t *testing.T) {
+	testCases := []struct {
+		limit             string
+		remaining         string
+		expectedRateLimit raiderio.RateLimitInfo
+	}{
+		{limit: "300", remaining: "120", expectedRateLimit: raiderio.RateLimitInfo{Known: true, Limit: 300, Remaining: 120}},
+		{limit: "300", remaining: "", expectedRateLimit: raiderio.RateLimitInfo{}},
+		{limit: "", remaining: "", expectedRateLimit: raiderio.RateLimitInfo{}},
+	}
+
+	for _, tc := range testCases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tc.limit != "" {
+				w.Header().Set("X-RateLimit-Limit", tc.limit)
+			}
+			if tc.remaining != "" {
+				w.Header().Set("X-RateLimit-Remaining", tc.remaining)
+			}
+			w.Write([]byte(`{"raids":[]}`))
+		}))
+
+		client := raiderio.NewClient()
+		client.ApiUrl = ts.URL
+		_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		ts.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if client.LastRateLimit() != tc.expectedRateLimit {
+			t.Fatalf("expected rate limit: %+v, got: %+v", tc.expectedRateLimit, client.LastRateLimit())
+		}
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	testCases := []struct {
+		retryAfter  string
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{retryAfter: "30", expectedMin: 30 * time.Second, expectedMax: 30 * time.Second},
+		{retryAfter: future, expectedMin: 58 * time.Minute, expectedMax: time.Hour},
+		{retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", expectedMin: 0, expectedMax: 0},
+		{retryAfter: "", expectedMin: 0, expectedMax: 0},
+	}
+
+	for _, tc := range testCases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tc.retryAfter != "" {
+				w.Header().Set("Retry-After", tc.retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"statusCode":429,"error":"Too Many Requests","message":"Rate limit exceeded"}`))
+		}))
+
+		client := raiderio.NewClient()
+		client.ApiUrl = ts.URL
+		_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		ts.Close()
+		if !errors.Is(err, raiderio.ErrRateLimited) {
+			t.Fatalf("expected error: %v, got: %v", raiderio.ErrRateLimited, err)
+		}
+
+		var rle *raiderio.RateLimitError
+		if !errors.As(err, &rle) {
+			t.Fatalf("expected a RateLimitError, got: %T", err)
+		}
+
+		if rle.RetryAfter() < tc.expectedMin || rle.RetryAfter() > tc.expectedMax {
+			t.Fatalf("expected retry after between: %v and %v, got: %v", tc.expectedMin, tc.expectedMax, rle.RetryAfter())
+		}
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	now := time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", now.Add(90*time.Second).Format(http.TimeFormat))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		advance            time.Duration
+		expectedRetryAfter time.Duration
+	}{
+		{advance: 0, expectedRetryAfter: 90 * time.Second},
+		{advance: time.Minute, expectedRetryAfter: 30 * time.Second},
+		{advance: 2 * time.Minute, expectedRetryAfter: 0},
+	}
+
+	for _, tc := range testCases {
+		client := raiderio.NewClient(raiderio.WithClock(func() time.Time {
+			return now.Add(tc.advance)
+		}))
+		client.ApiUrl = ts.URL
+
+		_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		var rle *raiderio.RateLimitError
+		if !errors.As(err, &rle) {
+			t.Fatalf("expected a RateLimitError, got: %v", err)
+		}
+
+		if rle.RetryAfter() != tc.expectedRetryAfter {
+			t.Fatalf("expected retry after: %v, got: %v", tc.expectedRetryAfter, rle.RetryAfter())
+		}
+	}
+}
+
+func TestResponseReadError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte(`{"raids":[`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+	if !errors.Is(err, raiderio.ErrResponseRead) {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrResponseRead, err)
+	}
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected error: %v, got: %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestWithStrictDecoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"raids":[{"slug":"nerubar-palace","new_field":true}]}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		opts        []raiderio.ClientOption
+		expectedErr error
+	}{
+		{opts: []raiderio.ClientOption{raiderio.WithStrictDecoding()}, expectedErr: raiderio.ErrDecode},
+		{opts: nil, expectedErr: nil},
+	}
+
+	for _, tc := range testCases {
+		client := raiderio.NewClient(tc.opts...)
+		client.ApiUrl = ts.URL
+
+		_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		if !errors.Is(err, tc.expectedErr) {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestWithSchemaWarnings(t *testing.T) {
+	testCases := []struct {
+		body           string
+		expectedFields []string
+	}{
+		{body: `{"name":"Highervalue","gear":{"items":{"head":{"item_id":1,"new_field":1}}},"new_field":true,"achievement_points":10}`,
+			expectedFields: []string{"gear.items.head.new_field", "new_field"}},
+		{body: `{"name":"Highervalue","Race":"Orc","mythic_plus_weekly_highest_level_runs":[{"dungeon":"Ara-Kara","x":1},{"y":2}]}`,
+			expectedFields: []string{"mythic_plus_weekly_highest_level_runs.x", "mythic_plus_weekly_highest_level_runs.y"}},
+		{body: `{"name":"Highervalue"}`, expectedFields: nil},
+	}
+
+	for _, tc := range testCases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tc.body))
+		}))
+
+		var endpoint string
+		var fields []string
+		client := raiderio.NewClient(raiderio.WithSchemaWarnings(func(e string, unknown []string) {
+			endpoint, fields = e, unknown
+		}))
+		client.ApiUrl = ts.URL
+
+		_, err := client.GetCharacter(defaultCtx, &raiderio.CharacterQuery{Region: raiderio.Regions.US, Realm: "illidan", Name: "highervalue"})
+		ts.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Join(fields, ",") != strings.Join(tc.expectedFields, ",") {
+			t.Fatalf("expected unknown fields: %v, got: %v", tc.expectedFields, fields)
+		}
+
+		if tc.expectedFields != nil && endpoint != "characters/profile" {
+			t.Fatalf("expected endpoint: %v, got: %v", "characters/profile", endpoint)
+		}
+	}
+}
+
+func TestWithDefaultRegionRealm(t *testing.T) {
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("region") + "/" + r.URL.Query().Get("realm")
+		w.Write([]byte(`{"name":"Highervalue"}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		opts          []raiderio.ClientOption
+		cq            *raiderio.CharacterQuery
+		expectedQuery string
+		expectedErr   error
+	}{
+		{opts: []raiderio.ClientOption{raiderio.WithDefaultRegion(raiderio.Regions.US), raiderio.WithDefaultRealm("illidan")},
+			cq: &raiderio.CharacterQuery{Name: "highervalue"}, expectedQuery: "us/illidan"},
+		{opts: []raiderio.ClientOption{raiderio.WithDefaultRegion(raiderio.Regions.US), raiderio.WithDefaultRealm("illidan")},
+			cq: &raiderio.CharacterQuery{Region: raiderio.Regions.EU, Realm: "draenor", Name: "highervalue"}, expectedQuery: "eu/draenor"},
+		{opts: []raiderio.ClientOption{raiderio.WithDefaultRegion(raiderio.Regions.US)},
+			cq: &raiderio.CharacterQuery{Name: "highervalue"}, expectedErr: raiderio.ErrInvalidRealm},
+		{opts: []raiderio.ClientOption{raiderio.WithDefaultRealm("illidan")},
+			cq: &raiderio.CharacterQuery{Name: "highervalue"}, expectedErr: raiderio.ErrInvalidRegion},
+		{opts: []raiderio.ClientOption{raiderio.WithDefaultRegion(raiderio.Regions.US), raiderio.WithDefaultRealm("us")},
+			cq: &raiderio.CharacterQuery{Name: "highervalue"}, expectedErr: raiderio.ErrRealmLooksLikeRegion},
+	}
+
+	for _, tc := range testCases {
+		query = ""
+		client := raiderio.NewClient(tc.opts...)
+		client.ApiUrl = ts.URL
+
+		_, err := client.GetCharacter(defaultCtx, tc.cq)
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if query != tc.expectedQuery {
+			t.Fatalf("expected region and realm: %v, got: %v", tc.expectedQuery, query)
+		}
+	}
+}
+
+func TestWithPerAttemptTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("name") {
+		case "slow":
+			time.Sleep(200 * time.Millisecond)
+		case "stalled":
+			// stalls partway through the body, after the headers are sent
+			w.Write([]byte(`{"name":`))
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`"Highervalue"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"Highervalue"}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		name        string
+		expectedErr error
+	}{
+		{name: "slow", expectedErr: raiderio.ErrApiTimeout},
+		{name: "stalled", expectedErr: raiderio.ErrApiTimeout},
+		{name: "fast", expectedErr: nil},
+	}
+
+	client := raiderio.NewClient(raiderio.WithPerAttemptTimeout(50 * time.Millisecond))
+	client.ApiUrl = ts.URL
+	for _, tc := range testCases {
+		_, err := client.GetCharacter(defaultCtx, &raiderio.CharacterQuery{Region: raiderio.Regions.US, Realm: "illidan", Name: tc.name})
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestGetRaw(t *testing.T) {
+	var requested string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.RequestURI()
+		w.Write([]byte(`{"cutoffs":{"p999":{"all":{"quantileMinValue":3500.5}}}}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		path         string
+		params       url.Values
+		expectedPath string
+		expectedErr  error
+	}{
+		{path: "mythic-plus/season-cutoffs", params: url.Values{"season": {"season-tww-2"}, "region": {"us"}},
+			expectedPath: "/mythic-plus/season-cutoffs?region=us&season=season-tww-2"},
+		{path: "/raiding/static-data", params: nil, expectedPath: "/raiding/static-data"},
+		{path: "https://example.com/api", expectedErr: raiderio.ErrInvalidPath},
+		{path: "//example.com/api", expectedErr: raiderio.ErrInvalidPath},
+		{path: "../admin", expectedErr: raiderio.ErrInvalidPath},
+		{path: "mythic-plus/%2e%2e/%2e%2e/admin", expectedErr: raiderio.ErrInvalidPath},
+		{path: "characters/profile?region=us", expectedErr: raiderio.ErrInvalidPath},
+		{path: "", expectedErr: raiderio.ErrInvalidPath},
+	}
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	for _, tc := range testCases {
+		requested = ""
+		raw, err := client.GetRaw(defaultCtx, tc.path, tc.params)
+		if err != tc.expectedErr {
+			t.Fatalf("%v expected error: %v, got: %v", tc.path, tc.expectedErr, err)
+		}
+
+		if requested != tc.expectedPath {
+			t.Fatalf("expected request: %v, got: %v", tc.expectedPath, requested)
+		}
+
+		if err == nil && !strings.Contains(string(raw), "quantileMinValue") {
+			t.Fatalf("unexpected raw response: %s", raw)
+		}
+	}
+}
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"raids":[]}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		opts        []raiderio.ClientOption
+		expectedErr bool
+	}{
+		{opts: nil, expectedErr: true},
+		{opts: []raiderio.ClientOption{raiderio.WithInsecureSkipVerify()}, expectedErr: false},
+	}
+
+	for _, tc := range testCases {
+		client := raiderio.NewClient(tc.opts...)
+		client.ApiUrl = ts.URL
+		_, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		if (err != nil) != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestWithETagCache(t *testing.T) {
+	var ifNoneMatch []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"raids":[{"slug":"nerubar-palace"}]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient(raiderio.WithETagCache(raiderio.NewMemoryETagCache()))
+	client.ApiUrl = ts.URL
+	for i := 0; i < 2; i++ {
+		raids, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(raids.Raids) != 1 || raids.Raids[0].Slug != "nerubar-palace" {
+			t.Fatalf("expected cached raids, got: %+v", raids)
+		}
+	}
+
+	expected := []string{"", `"v1"`}
+	if strings.Join(ifNoneMatch, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected If-None-Match headers: %v, got: %v", expected, ifNoneMatch)
+	}
+}
+
+// etagOnlyCache is an ETagCache which has lost the bodies of its entries
+type etagOnlyCache struct{}
+
+func (etagOnlyCache) Get(url string) (string, []byte, bool)    { return `"v1"`, nil, true }
+func (etagOnlyCache) Set(url string, etag string, body []byte) {}
+
+func TestWithETagCacheMissingBody(t *testing.T) {
+	var ifNoneMatch string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = r.Header.Get("If-None-Match")
+		if ifNoneMatch == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"raids":[{"slug":"nerubar-palace"}]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient(raiderio.WithETagCache(etagOnlyCache{}))
+	client.ApiUrl = ts.URL
+	raids, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ifNoneMatch != "" {
+		t.Fatalf("expected no If-None-Match header, got: %v", ifNoneMatch)
+	}
+
+	if len(raids.Raids) != 1 || raids.Raids[0].Slug != "nerubar-palace" {
+		t.Fatalf("expected raids, got: %+v", raids)
+	}
+}
+
+func TestWithETagCacheDefaultParams(t *testing.T) {
+	// the same resource version has the same ETag, whichever the access key
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"raids":[{"slug":"` + r.URL.Query().Get("access_key") + `"}]}`))
+	}))
+	defer ts.Close()
+
+	cache := raiderio.NewMemoryETagCache()
+	for _, key := range []string{"first", "second", "first"} {
+		client := raiderio.NewClient(raiderio.WithETagCache(cache),
+			raiderio.WithDefaultParams(url.Values{"access_key": {key}}))
+		client.ApiUrl = ts.URL
+
+		raids, err := client.GetRaids(defaultCtx, raiderio.Expansions.WarWithin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(raids.Raids) != 1 || raids.Raids[0].Slug != key {
+			t.Fatalf("expected raids for access key: %v, got: %+v", key, raids)
+		}
+	}
+}
+
+func TestWithDefaultParams(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"name":"Highervalue"}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient(raiderio.WithDefaultParams(url.Values{
+		"source": {"my-tool"},
+		"name":   {"overridden"},
+	}))
+	client.ApiUrl = ts.URL
+	_, err := client.GetCharacter(defaultCtx, &raiderio.CharacterQuery{
+		Region: raiderio.Regions.US,
+		Realm:  "illidan",
+		Name:   "highervalue",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		param         string
+		expectedValue string
+	}{
+		{param: "source", expectedValue: "my-tool"},
+		{param: "name", expectedValue: "highervalue"},
+		{param: "realm", expectedValue: "illidan"},
+	}
+
+	for _, tc := range testCases {
+		if query.Get(tc.param) != tc.expectedValue {
+			t.Fatalf("expected %v param: %v, got: %v", tc.param, tc.expectedValue, query.Get(tc.param))
+		}
+	}
+}
+
+func TestWithRequestIDFromContext(t *testing.T) {
+	type traceKey struct{}
+
+	var header string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Request-ID")
+		w.Write([]byte(`{"raids":[]}`))
+	}))
+	defer ts.Close()
+
+	var traced string
+	client := raiderio.NewClient(
+		raiderio.WithRequestIDFromContext(func(ctx context.Context) string {
+			id, _ := ctx.Value(traceKey{}).(string)
+			return id
+		}),
+		raiderio.WithConnTrace(func(ct raiderio.ConnTrace) {
+			traced = ct.RequestID
+		}),
+	)
+	client.ApiUrl = ts.URL
+
+	testCases := []struct {
+		ctx        context.Context
+		expectedID string
+	}{
+		{ctx: context.WithValue(defaultCtx, traceKey{}, "trace-1234"), expectedID: "trace-1234"},
+		{ctx: defaultCtx, expectedID: ""},
+	}
+
+	for _, tc := range testCases {
+		_, err := client.GetRaids(tc.ctx, raiderio.Expansions.WarWithin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if header != tc.expectedID || traced != tc.expectedID {
+			t.Fatalf("expected request id: %v, got header: %v trace: %v", tc.expectedID, header, traced)
+		}
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	testCases := []struct {
+		opts           []raiderio.ClientOption
+		expectedApiUrl string
+	}{
+		{opts: nil, expectedApiUrl: "https://raider.io/api/v1"},
+		{opts: []raiderio.ClientOption{raiderio.WithAPIVersion("v2")}, expectedApiUrl: "https://raider.io/api/v2"},
+		{opts: []raiderio.ClientOption{raiderio.WithBaseURL("http://localhost:8080/api/")}, expectedApiUrl: "http://localhost:8080/api/v1"},
+		{opts: []raiderio.ClientOption{raiderio.WithAPIVersion("/v2"), raiderio.WithBaseURL("http://localhost:8080")},
+			expectedApiUrl: "http://localhost:8080/v2"},
+	}
+
+	for _, tc := range testCases {
+		client := raiderio.NewClient(tc.opts...)
+		if client.ApiUrl != tc.expectedApiUrl {
+			t.Fatalf("expected api url: %v, got: %v", tc.expectedApiUrl, client.ApiUrl)
+		}
+	}
+}
+
+func TestWithRedirectPolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"raids":[]}`))
+	}))
+	defer other.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("expansion_id") {
+		case "9":
+			http.Redirect(w, r, other.URL+r.URL.Path, http.StatusMovedPermanently)
+		case "8":
+			http.Redirect(w, r, r.URL.String(), http.StatusMovedPermanently)
+		default:
+			w.Write([]byte(`{"raids":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	errPolicy := errors.New("redirect refused by policy")
+	refuse := func(req *http.Request, via []*http.Request) error {
+		return errPolicy
+	}
+	lastResponse := func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	testCases := []struct {
+		opts           []raiderio.ClientOption
+		expansion      raiderio.Expansion
+		expectedErrMsg string
+		expectedErr    error
+	}{
+		{opts: nil, expansion: raiderio.Expansions.Dragonflight},
+		{opts: nil, expansion: raiderio.Expansions.Shadowlands,
+			expectedErrMsg: "raiderio api request stopped after too many redirects"},
+		{opts: []raiderio.ClientOption{raiderio.WithRedirectPolicy(raiderio.SameHostRedirects(3))},
+			expansion: raiderio.Expansions.Dragonflight, expectedErrMsg: "raiderio api request redirected to another host"},
+		{opts: []raiderio.ClientOption{raiderio.WithRedirectPolicy(refuse)},
+			expansion: raiderio.Expansions.Dragonflight, expectedErr: errPolicy},
+		{opts: []raiderio.ClientOption{raiderio.WithRedirectPolicy(lastResponse)},
+			expansion: raiderio.Expansions.Dragonflight, expectedErr: raiderio.ErrRedirectNotFollowed},
+	}
+
+	for _, tc := range testCases {
+		client := raiderio.NewClient(tc.opts...)
+		client.ApiUrl = ts.URL
+
+		_, err := client.GetRaids(defaultCtx, tc.expansion)
+		if tc.expectedErr != nil {
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			continue
+		}
+
+		if err != nil && err.Error() != tc.expectedErrMsg {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErrMsg, err.Error())
+		}
+
+		if err == nil && tc.expectedErrMsg != "" {
+			t.Fatalf("expected error: %v", tc.expectedErrMsg)
+		}
+	}
+}
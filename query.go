@@ -0,0 +1,111 @@
+package raiderio
+
+// Validatable is implemented by every query type accepted by the client
+// Client methods validate their query before sending a request, so an
+// invalid query fails locally without a round trip to the api
+//
+// Region and realm requirements per query:
+//
+//	query                region    world region  realm
+//	CharacterQuery       required  not allowed   required
+//	GuildQuery           required  not allowed   required
+//	GuildBossKillQuery   required  not allowed   required
+//	RaidQuery            required  allowed       optional, not allowed with world
+//	MythicPlusRunsQuery  required  allowed       not accepted
+//
+// Realms belong to a single region, so a realm is never valid with the
+// world region
+type Validatable interface {
+	validate() error
+}
+
+var (
+	_ Validatable = (*CharacterQuery)(nil)
+	_ Validatable = (*GuildQuery)(nil)
+	_ Validatable = (*RaidQuery)(nil)
+	_ Validatable = (*GuildBossKillQuery)(nil)
+	_ Validatable = (*MythicPlusRunsQuery)(nil)
+)
+
+// validateRegionRealm checks the region and realm shared by the character,
+// guild and boss kill queries, which require a realm
+func validateRegionRealm(region *Region, realm string) error {
+	if region == nil {
+		return ErrInvalidRegion
+	}
+
+	if region.Slug == Regions.WORLD.Slug {
+		return ErrWorldRegionNotAllowed
+	}
+
+	if realm == "" {
+		return ErrInvalidRealm
+	}
+
+	if realmLooksLikeRegion(realm) {
+		return ErrRealmLooksLikeRegion
+	}
+
+	return nil
+}
+
+// NewCharacterQuery creates a CharacterQuery for a character, validating it
+// immediately so invalid input fails before a request is sent
+// Optional fields can be set on the returned query
+func NewCharacterQuery(region *Region, realm, name string) (*CharacterQuery, error) {
+	cq := &CharacterQuery{Region: region, Realm: realm, Name: name}
+	if err := cq.validate(); err != nil {
+		return nil, err
+	}
+	return cq, nil
+}
+
+// NewGuildQuery creates a GuildQuery for a guild, validating it immediately
+// so invalid input fails before a request is sent
+// Optional fields can be set on the returned query
+func NewGuildQuery(region *Region, realm, name string) (*GuildQuery, error) {
+	gq := &GuildQuery{Region: region, Realm: realm, Name: name}
+	if err := gq.validate(); err != nil {
+		return nil, err
+	}
+	return gq, nil
+}
+
+// NewRaidQuery creates a RaidQuery for a raid's rankings, validating it
+// immediately so invalid input fails before a request is sent
+// Realm, Limit and Page can be set on the returned query
+func NewRaidQuery(slug string, difficulty RaidDifficulty, region *Region) (*RaidQuery, error) {
+	rq := &RaidQuery{Slug: slug, Difficulty: difficulty, Region: region}
+	if err := rq.validate(); err != nil {
+		return nil, err
+	}
+	return rq, nil
+}
+
+// NewGuildBossKillQuery creates a GuildBossKillQuery, validating it
+// immediately so invalid input fails before a request is sent
+func NewGuildBossKillQuery(region *Region, realm, guildName, raidSlug, bossSlug string, difficulty RaidDifficulty) (*GuildBossKillQuery, error) {
+	q := &GuildBossKillQuery{
+		Region:     region,
+		Realm:      realm,
+		GuildName:  guildName,
+		RaidSlug:   raidSlug,
+		BossSlug:   bossSlug,
+		Difficulty: difficulty,
+	}
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// NewMythicPlusRunsQuery creates a MythicPlusRunsQuery for a dungeon's
+// leaderboard, validating it immediately so invalid input fails before a
+// request is sent. Season, Affixes and Page can be set on the returned query
+func NewMythicPlusRunsQuery(region *Region, dungeon string) (*MythicPlusRunsQuery, error) {
+	q := &MythicPlusRunsQuery{Region: region, Dungeon: dungeon}
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
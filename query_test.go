@@ -0,0 +1,199 @@
+package raiderio_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestNewQueries(t *testing.T) {
+	testCases := []struct {
+		name        string
+		newQuery    func() (interface{}, error)
+		expectedErr error
+	}{
+		{name: "character", expectedErr: nil, newQuery: func() (interface{}, error) {
+			return raiderio.NewCharacterQuery(raiderio.Regions.US, "illidan", "highervalue")
+		}},
+		{name: "character swapped realm", expectedErr: raiderio.ErrRealmLooksLikeRegion, newQuery: func() (interface{}, error) {
+			return raiderio.NewCharacterQuery(raiderio.Regions.US, "us", "highervalue")
+		}},
+		{name: "guild", expectedErr: nil, newQuery: func() (interface{}, error) {
+			return raiderio.NewGuildQuery(raiderio.Regions.US, "illidan", "warpath")
+		}},
+		{name: "guild no name", expectedErr: raiderio.ErrInvalidGuildName, newQuery: func() (interface{}, error) {
+			return raiderio.NewGuildQuery(raiderio.Regions.US, "illidan", "")
+		}},
+		{name: "raid", expectedErr: nil, newQuery: func() (interface{}, error) {
+			return raiderio.NewRaidQuery("nerubar-palace", raiderio.Difficulty.MythicRaid, raiderio.Regions.WORLD)
+		}},
+		{name: "raid no difficulty", expectedErr: raiderio.ErrInvalidRaidDiff, newQuery: func() (interface{}, error) {
+			return raiderio.NewRaidQuery("nerubar-palace", "", raiderio.Regions.US)
+		}},
+		{name: "boss kill", expectedErr: nil, newQuery: func() (interface{}, error) {
+			return raiderio.NewGuildBossKillQuery(raiderio.Regions.US, "illidan", "warpath", "nerubar-palace",
+				"ulgrax-the-devourer", raiderio.Difficulty.HeroicRaid)
+		}},
+		{name: "boss kill no boss", expectedErr: raiderio.ErrInvalidBoss, newQuery: func() (interface{}, error) {
+			return raiderio.NewGuildBossKillQuery(raiderio.Regions.US, "illidan", "warpath", "nerubar-palace",
+				"", raiderio.Difficulty.HeroicRaid)
+		}},
+		{name: "mythic plus runs", expectedErr: nil, newQuery: func() (interface{}, error) {
+			return raiderio.NewMythicPlusRunsQuery(raiderio.Regions.EU, "ara-kara-city-of-echoes")
+		}},
+		{name: "mythic plus runs no region", expectedErr: raiderio.ErrInvalidRegion, newQuery: func() (interface{}, error) {
+			return raiderio.NewMythicPlusRunsQuery(nil, "ara-kara-city-of-echoes")
+		}},
+	}
+
+	for _, tc := range testCases {
+		_, err := tc.newQuery()
+		if err != tc.expectedErr {
+			t.Fatalf("%v expected error: %v, got: %v", tc.name, tc.expectedErr, err)
+		}
+	}
+}
+
+func TestScopedRaidProgression(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"name":"Warpath"}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	testCases := []struct {
+		rawFields   []string
+		expectedErr error
+	}{
+		{rawFields: []string{"raid_progression"}},
+		{rawFields: []string{"raid_progression:10"}, expectedErr: raiderio.ErrScopedRaidProgression},
+		{rawFields: []string{"gear", " raid_progression:current-expansion"}, expectedErr: raiderio.ErrScopedRaidProgression},
+	}
+
+	for _, tc := range testCases {
+		_, charErr := client.GetCharacter(defaultCtx, &raiderio.CharacterQuery{
+			Region: raiderio.Regions.US, Realm: "illidan", Name: "highervalue", RawFields: tc.rawFields,
+		})
+		_, guildErr := client.GetGuild(defaultCtx, &raiderio.GuildQuery{
+			Region: raiderio.Regions.US, Realm: "illidan", Name: "warpath", RawFields: tc.rawFields,
+		})
+		if charErr != tc.expectedErr || guildErr != tc.expectedErr {
+			t.Fatalf("%v expected error: %v, got: %v and %v", tc.rawFields, tc.expectedErr, charErr, guildErr)
+		}
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected requests: %v, got: %v", 2, requests)
+	}
+
+	if !errors.Is(raiderio.ErrScopedRaidProgression, raiderio.ErrInvalidQuery) {
+		t.Fatalf("expected error to wrap: %v", raiderio.ErrInvalidQuery)
+	}
+}
+
+func TestRaidQueryLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"raidRankings":[]}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		limit       int
+		expectedErr error
+	}{
+		{limit: 0, expectedErr: nil},
+		{limit: raiderio.MaxRaidRankingLimit, expectedErr: nil},
+		{limit: raiderio.MaxRaidRankingLimit + 1, expectedErr: raiderio.ErrLimitTooLarge},
+		{limit: -1, expectedErr: raiderio.ErrLimitOutOfBounds},
+	}
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	for _, tc := range testCases {
+		_, err := client.GetRaidRankings(defaultCtx, &raiderio.RaidQuery{
+			Slug:       "nerubar-palace",
+			Difficulty: raiderio.Difficulty.MythicRaid,
+			Region:     raiderio.Regions.WORLD,
+			Limit:      tc.limit,
+		})
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+	}
+
+	if raiderio.ErrLimitTooLarge.Error() != "limit must be at most 100" {
+		t.Fatalf("expected error message: limit must be at most 100, got: %v", raiderio.ErrLimitTooLarge)
+	}
+}
+
+func TestQueryRegionRealmMatrix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"raidRankings":[]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	testCases := []struct {
+		query       string
+		region      *raiderio.Region
+		realm       string
+		expectedErr error
+	}{
+		{query: "character", region: raiderio.Regions.US, realm: "illidan"},
+		{query: "character", region: raiderio.Regions.US, expectedErr: raiderio.ErrInvalidRealm},
+		{query: "character", region: raiderio.Regions.WORLD, realm: "illidan", expectedErr: raiderio.ErrWorldRegionNotAllowed},
+		{query: "character", region: nil, realm: "illidan", expectedErr: raiderio.ErrInvalidRegion},
+		{query: "guild", region: raiderio.Regions.EU, realm: "draenor"},
+		{query: "guild", region: raiderio.Regions.EU, expectedErr: raiderio.ErrInvalidRealm},
+		{query: "guild", region: raiderio.Regions.WORLD, realm: "draenor", expectedErr: raiderio.ErrWorldRegionNotAllowed},
+		{query: "boss kill", region: raiderio.Regions.US, realm: "illidan"},
+		{query: "boss kill", region: raiderio.Regions.US, expectedErr: raiderio.ErrInvalidRealm},
+		{query: "boss kill", region: raiderio.Regions.WORLD, realm: "illidan", expectedErr: raiderio.ErrWorldRegionNotAllowed},
+		{query: "raid", region: raiderio.Regions.US, realm: "illidan"},
+		{query: "raid", region: raiderio.Regions.US},
+		{query: "raid", region: raiderio.Regions.WORLD},
+		{query: "raid", region: raiderio.Regions.WORLD, realm: "illidan", expectedErr: raiderio.ErrWorldRegionRealm},
+		{query: "raid", region: nil, expectedErr: raiderio.ErrInvalidRegion},
+		{query: "mythic plus runs", region: raiderio.Regions.WORLD},
+		{query: "mythic plus runs", region: nil, expectedErr: raiderio.ErrInvalidRegion},
+	}
+
+	for _, tc := range testCases {
+		var err error
+		switch tc.query {
+		case "character":
+			_, err = raiderio.NewCharacterQuery(tc.region, tc.realm, "highervalue")
+		case "guild":
+			_, err = raiderio.NewGuildQuery(tc.region, tc.realm, "warpath")
+		case "boss kill":
+			_, err = raiderio.NewGuildBossKillQuery(tc.region, tc.realm, "warpath", "nerubar-palace",
+				"ulgrax-the-devourer", raiderio.Difficulty.MythicRaid)
+		case "raid":
+			_, err = client.GetRaidRankings(defaultCtx, &raiderio.RaidQuery{
+				Slug:       "nerubar-palace",
+				Difficulty: raiderio.Difficulty.MythicRaid,
+				Region:     tc.region,
+				Realm:      tc.realm,
+			})
+		case "mythic plus runs":
+			_, err = raiderio.NewMythicPlusRunsQuery(tc.region, "all")
+		}
+
+		if err != tc.expectedErr {
+			t.Fatalf("%v region: %v realm: %q expected error: %v, got: %v", tc.query, tc.region, tc.realm, tc.expectedErr, err)
+		}
+	}
+
+	if !errors.Is(raiderio.ErrWorldRegionRealm, raiderio.ErrInvalidRealm) {
+		t.Fatalf("expected error to wrap: %v", raiderio.ErrInvalidRealm)
+	}
+}
@@ -1,10 +1,21 @@
 package raiderio
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// MaxRaidRankingLimit is the largest Limit the api accepts for a raid
+// rankings request. Larger limits are rejected with ErrLimitTooLarge, page
+// through the rankings instead. The api has no maximum page
+const MaxRaidRankingLimit int = 100
+
 // RaidQuery is a struct that represents the query parameters
 // sent for a raid request
 // Supports optional request fields: difficulty, region, realm, name
@@ -31,7 +42,7 @@ type RaidRanking struct {
 	Rank         int `json:"rank"`
 	RegionalRank int `json:"region_rank"`
 	Guild        struct {
-		Id      int    `json:"id"`
+		Id      int64  `json:"id"`
 		Name    string `json:"name"`
 		Faction string `json:"faction"`
 		Realm   Realm  `json:"realm"`
@@ -40,21 +51,155 @@ type RaidRanking struct {
 		Logo    string `json:"logo"`
 		Color   string `json:"color"`
 	} `json:"guild"`
-	EncountersDefeated []struct {
-		Slug           string `json:"slug"`
-		LastDefeatedAt string `json:"lastDefeated"`
-		FirstDefeated  string `json:"firstDefeated"`
-	} `json:"encountersDefeated"`
-	EncountersPulled []struct {
-		Id             int     `json:"id"`
+	EncountersDefeated []DefeatedEncounter `json:"encountersDefeated"`
+	EncountersPulled   []struct {
+		Id             int64   `json:"id"`
 		Slug           string  `json:"slug"`
 		Pulls          int     `json:"numPulls"`
 		PullsStartedAt string  `json:"pullStartedAt"`
-		BestPercent    float32 `json:"bestPercent"`
+		BestPercent    float64 `json:"bestPercent"`
 		IsDefeated     bool    `json:"isDefeated"`
 	} `json:"encountersPulled"`
 }
 
+// BossesDefeated returns the number of bosses the guild has defeated
+func (r RaidRanking) BossesDefeated() int {
+	return len(r.EncountersDefeated)
+}
+
+// BossesPulled returns the number of bosses the guild has pulled but not
+// yet defeated, ex: 1 for a guild at 7/8 progressing on the last boss
+func (r RaidRanking) BossesPulled() int {
+	var pulled int
+	for _, e := range r.EncountersPulled {
+		if !e.IsDefeated {
+			pulled++
+		}
+	}
+	return pulled
+}
+
+// SortByRank sorts the raid rankings in place by world rank
+// Ties are broken by regional rank, then by the original order
+func (r *RaidRankings) SortByRank() {
+	sort.SliceStable(r.RaidRanking, func(i, j int) bool {
+		a, b := r.RaidRanking[i], r.RaidRanking[j]
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		return a.RegionalRank < b.RegionalRank
+	})
+}
+
+// SortByRegionRank sorts the raid rankings in place by regional rank
+// Ties are broken by world rank, then by the original order
+func (r *RaidRankings) SortByRegionRank() {
+	sort.SliceStable(r.RaidRanking, func(i, j int) bool {
+		a, b := r.RaidRanking[i], r.RaidRanking[j]
+		if a.RegionalRank != b.RegionalRank {
+			return a.RegionalRank < b.RegionalRank
+		}
+		return a.Rank < b.Rank
+	})
+}
+
+// IsEmpty reports whether the response has no ranked guilds
+// The api answers a valid query with no ranked guilds, ex: a new or low
+// population realm, or a page past the last ranking, with an empty list
+// rather than an error, so IsEmpty is the way to tell the case apart
+func (r *RaidRankings) IsEmpty() bool {
+	return len(r.RaidRanking) == 0
+}
+
+// TopN returns the first n raid rankings in their current order
+// Returns every ranking if n is larger than the number of rankings
+func (r *RaidRankings) TopN(n int) []RaidRanking {
+	if n <= 0 {
+		return []RaidRanking{}
+	}
+
+	if n > len(r.RaidRanking) {
+		n = len(r.RaidRanking)
+	}
+	return r.RaidRanking[:n]
+}
+
+// WriteCSV writes the raid rankings to w as CSV, with a header row
+// Columns: rank, region_rank, guild, realm, region, faction, bosses_defeated
+func (r *RaidRankings) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"rank", "region_rank", "guild", "realm", "region", "faction", "bosses_defeated"})
+	if err != nil {
+		return err
+	}
+
+	for _, rr := range r.RaidRanking {
+		err = cw.Write([]string{
+			strconv.Itoa(rr.Rank),
+			strconv.Itoa(rr.RegionalRank),
+			rr.Guild.Name,
+			rr.Guild.Realm.Name,
+			rr.Guild.Region.Slug,
+			rr.Guild.Faction,
+			strconv.Itoa(len(rr.EncountersDefeated)),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// DefeatedEncounter is a struct that represents a boss a guild has
+// defeated in a raid rankings response
+// Timestamps are RFC3339 strings as returned by the api
+type DefeatedEncounter struct {
+	Slug           string `json:"slug"`
+	LastDefeatedAt string `json:"lastDefeated"`
+	FirstDefeated  string `json:"firstDefeated"`
+}
+
+// FirstDefeatedTime parses the time the guild first defeated the boss
+func (e *DefeatedEncounter) FirstDefeatedTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, e.FirstDefeated)
+}
+
+// LastDefeatedTime parses the time the guild last defeated the boss
+func (e *DefeatedEncounter) LastDefeatedTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, e.LastDefeatedAt)
+}
+
+// BossesInKillOrder returns the slugs of the defeated encounters, sorted
+// by the time they were first defeated
+// Encounters with a missing or unparsable first defeated timestamp are skipped
+func (r *RaidRanking) BossesInKillOrder() []string {
+	type kill struct {
+		slug string
+		at   time.Time
+	}
+
+	var kills []kill
+	for _, e := range r.EncountersDefeated {
+		at, err := e.FirstDefeatedTime()
+		if err != nil {
+			continue
+		}
+		kills = append(kills, kill{slug: e.Slug, at: at})
+	}
+
+	sort.SliceStable(kills, func(i, j int) bool {
+		return kills[i].at.Before(kills[j].at)
+	})
+
+	slugs := make([]string, 0, len(kills))
+	for _, k := range kills {
+		slugs = append(slugs, k.slug)
+	}
+	return slugs
+}
+
 // RaidProgression is a struct that contains the raid progression of a guild
 // in a guild profile response
 type RaidProgression struct {
@@ -65,6 +210,80 @@ type RaidProgression struct {
 	MythicKills int    `json:"mythic_bosses_killed"`
 }
 
+// HighestDifficulty returns the highest difficulty the guild has killed a
+// boss on, ex: mythic for a guild at 8/8 H and 2/8 M
+// Returns an empty difficulty when no kills are recorded
+func (p RaidProgression) HighestDifficulty() RaidDifficulty {
+	switch {
+	case p.MythicKills > 0:
+		return Difficulty.MythicRaid
+	case p.HeroicKills > 0:
+		return Difficulty.HeroicRaid
+	case p.NormalKills > 0:
+		return Difficulty.NormalRaid
+	}
+	return ""
+}
+
+// ParseSummary parses the compact progression summary, ex: "8/8 M"
+// into the number of bosses killed, the total number of bosses and
+// the difficulty the summary refers to
+// Returns ErrInvalidSummary if the summary is not in the expected form
+func (p RaidProgression) ParseSummary() (killed int, total int, difficulty RaidDifficulty, err error) {
+	parts := strings.Fields(p.Summary)
+	if len(parts) != 2 {
+		return 0, 0, "", ErrInvalidSummary
+	}
+
+	counts := strings.Split(parts[0], "/")
+	if len(counts) != 2 {
+		return 0, 0, "", ErrInvalidSummary
+	}
+
+	killed, err = strconv.Atoi(counts[0])
+	if err != nil {
+		return 0, 0, "", ErrInvalidSummary
+	}
+
+	total, err = strconv.Atoi(counts[1])
+	if err != nil || killed < 0 || killed > total {
+		return 0, 0, "", ErrInvalidSummary
+	}
+
+	switch strings.ToUpper(parts[1]) {
+	case "N":
+		difficulty = Difficulty.NormalRaid
+	case "H":
+		difficulty = Difficulty.HeroicRaid
+	case "M":
+		difficulty = Difficulty.MythicRaid
+	default:
+		return 0, 0, "", ErrInvalidSummary
+	}
+
+	return killed, total, difficulty, nil
+}
+
+// GuildRaidRankings contains the raid rankings of a guild in a guild
+// profile response, keyed by raid slug
+type GuildRaidRankings map[string]GuildRaidRanking
+
+// UnmarshalJSON decodes the raid rankings. An empty array, returned by the
+// api for guilds with no rankings, decodes as no raids
+func (r *GuildRaidRankings) UnmarshalJSON(b []byte) error {
+	if emptyJSONArray(b) {
+		*r = GuildRaidRankings{}
+		return nil
+	}
+
+	var rankings map[string]GuildRaidRanking
+	if err := json.Unmarshal(b, &rankings); err != nil {
+		return err
+	}
+	*r = rankings
+	return nil
+}
+
 // GuildRaidRanking is a struct that contains the raid rankings of a guild
 // in a guild profile response
 // Includes Normal Heroic and Mythic rankings
@@ -89,6 +308,24 @@ type GuildRaidRanking struct {
 	} `json:"mythic"`
 }
 
+// ProgressedDifficulties returns the difficulties the guild is ranked in,
+// those with a non zero world, region or realm rank, from normal to mythic
+func (r GuildRaidRanking) ProgressedDifficulties() []RaidDifficulty {
+	difficulties := []RaidDifficulty{}
+	if r.Normal.World != 0 || r.Normal.Region != 0 || r.Normal.Realm != 0 {
+		difficulties = append(difficulties, Difficulty.NormalRaid)
+	}
+
+	if r.Heroic.World != 0 || r.Heroic.Region != 0 || r.Heroic.Realm != 0 {
+		difficulties = append(difficulties, Difficulty.HeroicRaid)
+	}
+
+	if r.Mythic.World != 0 || r.Mythic.Region != 0 || r.Mythic.Realm != 0 {
+		difficulties = append(difficulties, Difficulty.MythicRaid)
+	}
+	return difficulties
+}
+
 // Raids is a struct that represents the response from a
 // raid static data request
 type Raids struct {
@@ -98,7 +335,7 @@ type Raids struct {
 // Raid is a struct that represents a raid in a raid static
 // data response. Includes raid encounters and other static data
 type Raid struct {
-	Id        int    `json:"id"`
+	Id        int64  `json:"id"`
 	Slug      string `json:"slug"`
 	Name      string `json:"name"`
 	ShortName string `json:"short_name"`
@@ -124,7 +361,7 @@ type Raid struct {
 // Encounter is a struct that represents an encounter in a raid
 // in a raid static data response
 type Encounter struct {
-	Id   int    `json:"id"`
+	Id   int64  `json:"id"`
 	Slug string `json:"slug"`
 	Name string `json:"name"`
 }
@@ -152,6 +389,44 @@ type BossKill struct {
 	Roster []Character
 }
 
+// RosterQueries returns a CharacterQuery for each roster member, to fetch
+// their current profiles, ex: with GetCharacter
+// Roster members with no name or realm, or an unknown or world region,
+// cannot be queried. Their names are returned as skipped, in roster order
+func (k *BossKill) RosterQueries() ([]*CharacterQuery, []string) {
+	queries := []*CharacterQuery{}
+	var skipped []string
+	for _, c := range k.Roster {
+		region := RegionBySlug(c.Region)
+		if c.Name == "" || c.Realm == "" || region == nil || region == Regions.WORLD {
+			skipped = append(skipped, c.Name)
+			continue
+		}
+		queries = append(queries, &CharacterQuery{Region: region, Realm: c.Realm, Name: c.Name})
+	}
+	return queries, skipped
+}
+
+// ComputedAvgItemLevel returns the average equipped item level of the
+// roster, to cross check against the reported Kill.ItemLevelEquippedAvg
+// Roster item levels are whole numbers, truncated from the api's values, so
+// the computed average can be slightly lower than the reported one
+// Roster members with no item level are skipped. Returns 0 for an empty roster
+func (k *BossKill) ComputedAvgItemLevel() float64 {
+	total, count := 0, 0
+	for _, c := range k.Roster {
+		if c.Gear.ItemLevelEquipped != 0 {
+			total += c.Gear.ItemLevelEquipped
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(count)
+}
+
 // BossKillData provides metadata for the guilds first boss kill
 // Includes timestamps and Item Levels etc...
 type BossKillData struct {
@@ -159,9 +434,25 @@ type BossKillData struct {
 	DefeatedAt           time.Time     `json:"defeatedAt"`
 	Duration             time.Duration `json:"duration"`
 	IsSuccess            bool          `json:"isSuccess"`
-	ItemLevelEquippedAvg float32       `json:"itemLevelEquippedAvg"`
-	ItemLevelEquippedMax float32       `json:"itemLevelEquippedMax"`
-	ItemLevelEquippedMin float32       `json:"itemLevelEquippedMin"`
+	ItemLevelEquippedAvg float64       `json:"itemLevelEquippedAvg"`
+	ItemLevelEquippedMax float64       `json:"itemLevelEquippedMax"`
+	ItemLevelEquippedMin float64       `json:"itemLevelEquippedMin"`
+}
+
+// DaysSinceKill returns the number of whole days between the kill and now
+// Days are counted as elapsed 24 hour periods, so the result does not depend
+// on the time zone of either time
+// Returns -1 when DefeatedAt is missing, and 0 when now is before the kill
+func (d BossKillData) DaysSinceKill(now time.Time) int {
+	if d.DefeatedAt.IsZero() {
+		return -1
+	}
+
+	elapsed := now.Sub(d.DefeatedAt)
+	if elapsed < 0 {
+		return 0
+	}
+	return int(elapsed / (24 * time.Hour))
 }
 
 // The following two structs are unexported, for use within the package
@@ -175,14 +466,15 @@ type bossKillResp struct {
 		DefeatedAt           time.Time `json:"defeatedAt"`
 		DurationMs           int       `json:"durationMs"`
 		IsSuccess            bool      `json:"isSuccess"`
-		ItemLevelEquippedAvg float32   `json:"itemLevelEquippedAvg"`
-		ItemLevelEquippedMax float32   `json:"itemLevelEquippedMax"`
-		ItemLevelEquippedMin float32   `json:"itemLevelEquippedMin"`
+		ItemLevelEquippedAvg float64   `json:"itemLevelEquippedAvg"`
+		ItemLevelEquippedMax float64   `json:"itemLevelEquippedMax"`
+		ItemLevelEquippedMin float64   `json:"itemLevelEquippedMin"`
 	}
 	Roster []bossKillCharacter `json:"roster"`
 }
 type bossKillCharacter struct {
 	Character struct {
+		ID    int    `json:"id"`
 		Name  string `json:"name"`
 		Class struct {
 			Slug string `json:"slug"`
@@ -200,12 +492,13 @@ type bossKillCharacter struct {
 		Region struct {
 			Slug string `json:"slug"`
 		} `json:"region"`
-		ItemLevelEquipped float32 `json:"itemLevelEquipped"`
+		ItemLevelEquipped float64 `json:"itemLevelEquipped"`
 	} `json:"character"`
 }
 
 // GuildBossKillQuery requires all fields to be valid when sending
 // a request to the api. Use GetRaids() to see a list of raids and bosses
+// The query always returns the guild's first kill of the boss
 type GuildBossKillQuery struct {
 	Region     *Region
 	Realm      string
@@ -218,11 +511,13 @@ type GuildBossKillQuery struct {
 // Current /guild/boss-kill api returns an enormous json
 // structure for each character in the raid roster
 // this library offers a simplified version of the data set
+// Missing or null nested objects (spec, talentLoadout etc...) are left
+// as zero values rather than treated as errors
 func unmarshalGuildBossKill(b []byte) (*BossKill, error) {
 	resp := bossKillResp{}
 	err := json.Unmarshal(b, &resp)
 	if err != nil {
-		return nil, err
+		return nil, errors.New("error unmarshalling guild boss kill")
 	}
 
 	kd := BossKillData{
@@ -251,6 +546,7 @@ func unmarshalBossKillRoster(k *bossKillResp) []Character {
 			LoadoutText: c.Character.TalentLoadout.LoadoutText,
 		}
 		char := Character{
+			ID:            c.Character.ID,
 			Name:          c.Character.Name,
 			Class:         c.Character.Class.Slug,
 			Spec:          c.Character.Spec.Slug,
@@ -264,13 +560,12 @@ func unmarshalBossKillRoster(k *bossKillResp) []Character {
 	return chars
 }
 
-func validateGuildBossKillQuery(q *GuildBossKillQuery) error {
-	if q.Region == nil {
-		return ErrInvalidRegion
-	}
-
-	if q.Realm == "" {
-		return ErrInvalidRealm
+// validate validates a GuildBossKillQuery struct
+// ensures that the required parameters are not empty
+func (q *GuildBossKillQuery) validate() error {
+	err := validateRegionRealm(q.Region, q.Realm)
+	if err != nil {
+		return err
 	}
 
 	if q.GuildName == "" {
@@ -285,7 +580,7 @@ func validateGuildBossKillQuery(q *GuildBossKillQuery) error {
 		return ErrInvalidBoss
 	}
 
-	if q.Difficulty == "" || !raidDifficltyValid(q.Difficulty) {
+	if !raidDifficltyValid(q.Difficulty) {
 		return ErrInvalidRaidDiff
 	}
 
@@ -296,7 +591,7 @@ func validateGuildBossKillQuery(q *GuildBossKillQuery) error {
 // making an http request to the api with an invalid difficulty
 // results in an empty result instead of an error message. So
 // we add the error by checking for valid difficulty before sending
-// the request to the api
+// the request to the api. An empty difficulty is invalid
 func raidDifficltyValid(d RaidDifficulty) bool {
 	if d == Difficulty.NormalRaid || d == Difficulty.HeroicRaid || d == Difficulty.MythicRaid {
 		return true
@@ -305,14 +600,14 @@ func raidDifficltyValid(d RaidDifficulty) bool {
 	return false
 }
 
-// validateRaidQuery validates a RaidQuery struct
+// validate validates a RaidQuery struct
 // ensures that the required parameters are not empty
-func validateRaidRankingsQuery(rq *RaidQuery) error {
+func (rq *RaidQuery) validate() error {
 	if rq.Slug == "" {
 		return ErrInvalidRaidName
 	}
 
-	if rq.Difficulty == "" || !raidDifficltyValid(rq.Difficulty) {
+	if !raidDifficltyValid(rq.Difficulty) {
 		return ErrInvalidRaidDiff
 	}
 
@@ -320,10 +615,22 @@ func validateRaidRankingsQuery(rq *RaidQuery) error {
 		return ErrInvalidRegion
 	}
 
+	if rq.Realm != "" && rq.Region.Slug == Regions.WORLD.Slug {
+		return ErrWorldRegionRealm
+	}
+
+	if realmLooksLikeRegion(rq.Realm) {
+		return ErrRealmLooksLikeRegion
+	}
+
 	if rq.Limit < 0 {
 		return ErrLimitOutOfBounds
 	}
 
+	if rq.Limit > MaxRaidRankingLimit {
+		return ErrLimitTooLarge
+	}
+
 	if rq.Page < 0 {
 		return ErrPageOutOfBounds
 	}
@@ -339,3 +646,109 @@ func (r *Raids) GetRaidBySlug(slug string) (*Raid, error) {
 	}
 	return nil, ErrInvalidRaid
 }
+
+// RaidForBoss returns the raid containing a boss, and the boss's encounter
+// Returns ErrInvalidBoss if no raid has an encounter with the boss slug
+// If more than one raid has the boss slug, the first raid is returned
+func (r *Raids) RaidForBoss(bossSlug string) (*Raid, *Encounter, error) {
+	for i := range r.Raids {
+		raid := &r.Raids[i]
+		for j := range raid.Encounters {
+			if raid.Encounters[j].Slug == bossSlug {
+				return raid, &raid.Encounters[j], nil
+			}
+		}
+	}
+	return nil, nil, ErrInvalidBoss
+}
+
+// AllEncounters returns every encounter of every raid, in raid order
+func (r *Raids) AllEncounters() []Encounter {
+	var encounters []Encounter
+	for _, raid := range r.Raids {
+		encounters = append(encounters, raid.Encounters...)
+	}
+	return encounters
+}
+
+// EncounterByRaid returns the encounters of every raid, keyed by raid slug
+func (r *Raids) EncounterByRaid() map[string][]Encounter {
+	encounters := make(map[string][]Encounter, len(r.Raids))
+	for _, raid := range r.Raids {
+		encounters[raid.Slug] = raid.Encounters
+	}
+	return encounters
+}
+
+// RaidWindow is a struct that represents the dates a raid is open in a region
+// End is zero for raids with no announced end, usually the current tier
+type RaidWindow struct {
+	Slug   string
+	Name   string
+	Start  time.Time
+	End    time.Time
+	Active bool
+}
+
+// Schedule returns the start and end times of each raid in the given
+// region, sorted chronologically by start. now decides which raids are
+// Active, those which started and have not ended
+// Raids with no start time in the region are skipped, the world region
+// has no schedule and returns an empty slice
+func (r *Raids) Schedule(region *Region, now time.Time) []RaidWindow {
+	windows := []RaidWindow{}
+	if region == nil {
+		return windows
+	}
+
+	for _, raid := range r.Raids {
+		starts, ends := raid.regionDates(region)
+		start, err := time.Parse(time.RFC3339, starts)
+		if err != nil {
+			continue
+		}
+
+		w := RaidWindow{Slug: raid.Slug, Name: raid.Name, Start: start}
+		if end, err := time.Parse(time.RFC3339, ends); err == nil {
+			w.End = end
+		}
+
+		w.Active = !now.Before(w.Start) && (w.End.IsZero() || now.Before(w.End))
+		windows = append(windows, w)
+	}
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+	return windows
+}
+
+// LatestRaidSlug returns the slug of the most recently started raid in the
+// given region, ex: the current tier, so it does not need to be hardcoded
+// Returns ErrInvalidRaid if no raid has started in the region by now
+func (r *Raids) LatestRaidSlug(region *Region, now time.Time) (string, error) {
+	windows := r.Schedule(region, now)
+	for i := len(windows) - 1; i >= 0; i-- {
+		if !now.Before(windows[i].Start) {
+			return windows[i].Slug, nil
+		}
+	}
+	return "", ErrInvalidRaid
+}
+
+// regionDates returns the raid's start and end dates for a region
+func (raid *Raid) regionDates(region *Region) (starts, ends string) {
+	switch region.Slug {
+	case Regions.US.Slug:
+		return raid.Starts.Us, raid.Ends.Us
+	case Regions.EU.Slug:
+		return raid.Starts.Eu, raid.Ends.Eu
+	case Regions.TW.Slug:
+		return raid.Starts.Tw, raid.Ends.Tw
+	case Regions.KR.Slug:
+		return raid.Starts.Kr, raid.Ends.Kr
+	case Regions.CN.Slug:
+		return raid.Starts.Cn, raid.Ends.Cn
+	}
+	return "", ""
+}
@@ -0,0 +1,61 @@
+//go:build go1.18
+// +build go1.18
+
+package raiderio
+
+import "testing"
+
+const bossKillSeed = `{
+	"kill": {
+		"pulledAt": "2022-12-21T03:02:11.000Z",
+		"defeatedAt": "2022-12-21T03:07:40.000Z",
+		"durationMs": 329000,
+		"isSuccess": true,
+		"itemLevelEquippedAvg": 403.5,
+		"itemLevelEquippedMax": 410.1,
+		"itemLevelEquippedMin": 398.2
+	},
+	"roster": [
+		{
+			"character": {
+				"name": "Drbananaphd",
+				"class": {"slug": "priest"},
+				"spec": {"slug": "holy"},
+				"talentLoadout": {"loadoutSpecId": 257, "loadoutText": "BAQAAAAAAAAAAAAAAAAAAAAAAA"},
+				"realm": {"slug": "illidan"},
+				"region": {"slug": "us"},
+				"itemLevelEquipped": 405.3
+			}
+		}
+	]
+}`
+
+// FuzzUnmarshalGuildBossKill ensures malformed boss kill responses
+// produce an error instead of a panic
+func FuzzUnmarshalGuildBossKill(f *testing.F) {
+	seeds := []string{
+		bossKillSeed,
+		`{"kill": null, "roster": null}`,
+		`{"kill": {}, "roster": [{}]}`,
+		`{"roster": [{"character": null}]}`,
+		`{"roster": [{"character": {"name": "a", "spec": null, "talentLoadout": null}}]}`,
+		`{"roster": {}}`,
+		`{"kill": {"pulledAt": "not a time"}}`,
+		`[]`,
+		``,
+	}
+	for _, s := range seeds {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		k, err := unmarshalGuildBossKill(b)
+		if err != nil && k != nil {
+			t.Fatalf("expected nil boss kill alongside error: %v", err)
+		}
+
+		if err == nil && k == nil {
+			t.Fatalf("expected boss kill when no error is returned")
+		}
+	})
+}
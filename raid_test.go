@@ -1,7 +1,12 @@
 package raiderio_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/tmaffia/raiderio"
 )
@@ -30,3 +35,545 @@ func TestGetRaidBySlug(t *testing.T) {
 		}
 	}
 }
+
+func TestParseSummary(t *testing.T) {
+	testCases := []struct {
+		summary            string
+		expectedKilled     int
+		expectedTotal      int
+		expectedDifficulty raiderio.RaidDifficulty
+		expectedErrMsg     string
+	}{
+		{summary: "8/8 M", expectedKilled: 8, expectedTotal: 8, expectedDifficulty: raiderio.Difficulty.MythicRaid},
+		{summary: "8/8 H", expectedKilled: 8, expectedTotal: 8, expectedDifficulty: raiderio.Difficulty.HeroicRaid},
+		{summary: "3/8 M", expectedKilled: 3, expectedTotal: 8, expectedDifficulty: raiderio.Difficulty.MythicRaid},
+		{summary: "0/9 N", expectedKilled: 0, expectedTotal: 9, expectedDifficulty: raiderio.Difficulty.NormalRaid},
+		{summary: "", expectedErrMsg: "invalid raid progression summary"},
+		{summary: "8/8", expectedErrMsg: "invalid raid progression summary"},
+		{summary: "9/8 M", expectedErrMsg: "invalid raid progression summary"},
+		{summary: "a/8 M", expectedErrMsg: "invalid raid progression summary"},
+		{summary: "8/8 X", expectedErrMsg: "invalid raid progression summary"},
+	}
+
+	for _, tc := range testCases {
+		p := raiderio.RaidProgression{Summary: tc.summary}
+		killed, total, difficulty, err := p.ParseSummary()
+		if err != nil && err.Error() != tc.expectedErrMsg {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErrMsg, err.Error())
+		}
+
+		if err == nil && tc.expectedErrMsg != "" {
+			t.Fatalf("summary: %v expected error: %v", tc.summary, tc.expectedErrMsg)
+		}
+
+		if err == nil && (killed != tc.expectedKilled || total != tc.expectedTotal || difficulty != tc.expectedDifficulty) {
+			t.Fatalf("summary: %v, got: %d/%d %v, expected: %d/%d %v", tc.summary,
+				killed, total, difficulty, tc.expectedKilled, tc.expectedTotal, tc.expectedDifficulty)
+		}
+	}
+}
+
+func TestHighestDifficulty(t *testing.T) {
+	testCases := []struct {
+		progression        raiderio.RaidProgression
+		expectedDifficulty raiderio.RaidDifficulty
+	}{
+		{progression: raiderio.RaidProgression{NormalKills: 8, HeroicKills: 8, MythicKills: 2}, expectedDifficulty: raiderio.Difficulty.MythicRaid},
+		{progression: raiderio.RaidProgression{NormalKills: 8, HeroicKills: 5}, expectedDifficulty: raiderio.Difficulty.HeroicRaid},
+		{progression: raiderio.RaidProgression{NormalKills: 1}, expectedDifficulty: raiderio.Difficulty.NormalRaid},
+		{progression: raiderio.RaidProgression{MythicKills: 1}, expectedDifficulty: raiderio.Difficulty.MythicRaid},
+		{progression: raiderio.RaidProgression{Summary: "0/8 N", Bosses: 8}, expectedDifficulty: ""},
+	}
+
+	for _, tc := range testCases {
+		if d := tc.progression.HighestDifficulty(); d != tc.expectedDifficulty {
+			t.Fatalf("expected highest difficulty: %q, got: %q", tc.expectedDifficulty, d)
+		}
+	}
+}
+
+func TestBossesInKillOrder(t *testing.T) {
+	r := raiderio.RaidRanking{
+		EncountersDefeated: []raiderio.DefeatedEncounter{
+			{Slug: "sennarth", FirstDefeated: "2022-12-28T02:10:00.000Z"},
+			{Slug: "eranog", FirstDefeated: "2022-12-21T00:05:00.000Z"},
+			{Slug: "raszageth", FirstDefeated: ""},
+			{Slug: "terros", FirstDefeated: "2022-12-20T21:30:00-05:00"},
+		},
+	}
+	expected := []string{"eranog", "terros", "sennarth"}
+
+	order := r.BossesInKillOrder()
+	if len(order) != len(expected) {
+		t.Fatalf("expected kill order: %v, got: %v", expected, order)
+	}
+
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected kill order: %v, got: %v", expected, order)
+		}
+	}
+}
+
+func TestBossesDefeatedPulled(t *testing.T) {
+	testCases := []struct {
+		response         string
+		expectedDefeated int
+		expectedPulled   int
+	}{
+		{response: `{"encountersDefeated":[{"slug":"ulgrax-the-devourer"},{"slug":"the-bloodbound-horror"}],
+			"encountersPulled":[{"slug":"sikran","numPulls":42,"bestPercent":12.5,"isDefeated":false}]}`,
+			expectedDefeated: 2, expectedPulled: 1},
+		{response: `{"encountersDefeated":[{"slug":"ulgrax-the-devourer"}],
+			"encountersPulled":[{"slug":"ulgrax-the-devourer","isDefeated":true}]}`,
+			expectedDefeated: 1, expectedPulled: 0},
+		{response: `{}`},
+	}
+
+	for _, tc := range testCases {
+		var r raiderio.RaidRanking
+		err := json.Unmarshal([]byte(tc.response), &r)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if r.BossesDefeated() != tc.expectedDefeated || r.BossesPulled() != tc.expectedPulled {
+			t.Fatalf("expected defeated: %v pulled: %v, got: %v pulled: %v",
+				tc.expectedDefeated, tc.expectedPulled, r.BossesDefeated(), r.BossesPulled())
+		}
+	}
+}
+
+func TestSortRaidRankings(t *testing.T) {
+	rankings := raiderio.RaidRankings{
+		RaidRanking: []raiderio.RaidRanking{
+			{Rank: 30, RegionalRank: 2},
+			{Rank: 10, RegionalRank: 1},
+			{Rank: 20, RegionalRank: 2},
+			{Rank: 5, RegionalRank: 3},
+		},
+	}
+
+	rankings.SortByRank()
+	expectedRanks := []int{5, 10, 20, 30}
+	for i, r := range rankings.RaidRanking {
+		if r.Rank != expectedRanks[i] {
+			t.Fatalf("sort by rank, position %d expected rank: %d, got: %d", i, expectedRanks[i], r.Rank)
+		}
+	}
+
+	rankings.SortByRegionRank()
+	expectedRanks = []int{10, 20, 30, 5}
+	for i, r := range rankings.RaidRanking {
+		if r.Rank != expectedRanks[i] {
+			t.Fatalf("sort by region rank, position %d expected rank: %d, got: %d", i, expectedRanks[i], r.Rank)
+		}
+	}
+
+	testCases := []struct {
+		n           int
+		expectedLen int
+	}{
+		{n: 2, expectedLen: 2},
+		{n: 10, expectedLen: 4},
+		{n: 0, expectedLen: 0},
+		{n: -1, expectedLen: 0},
+	}
+
+	for _, tc := range testCases {
+		top := rankings.TopN(tc.n)
+		if len(top) != tc.expectedLen {
+			t.Fatalf("top %d expected length: %d, got: %d", tc.n, tc.expectedLen, len(top))
+		}
+	}
+}
+
+func TestAllEncounters(t *testing.T) {
+	raids := raiderio.Raids{
+		Raids: []raiderio.Raid{
+			{Slug: "vault-of-the-incarnates", Encounters: []raiderio.Encounter{{Slug: "eranog"}, {Slug: "terros"}}},
+			{Slug: "aberrus-the-shadowed-crucible", Encounters: []raiderio.Encounter{{Slug: "kazzara"}}},
+		},
+	}
+
+	all := raids.AllEncounters()
+	expected := []string{"eranog", "terros", "kazzara"}
+	if len(all) != len(expected) {
+		t.Fatalf("expected %d encounters, got: %d", len(expected), len(all))
+	}
+
+	for i := range expected {
+		if all[i].Slug != expected[i] {
+			t.Fatalf("expected encounter: %v, got: %v", expected[i], all[i].Slug)
+		}
+	}
+
+	byRaid := raids.EncounterByRaid()
+	if len(byRaid["vault-of-the-incarnates"]) != 2 || len(byRaid["aberrus-the-shadowed-crucible"]) != 1 {
+		t.Fatalf("unexpected encounters by raid: %v", byRaid)
+	}
+}
+
+func TestRaidForBoss(t *testing.T) {
+	raids := raiderio.Raids{
+		Raids: []raiderio.Raid{
+			{Slug: "vault-of-the-incarnates", Encounters: []raiderio.Encounter{{Slug: "eranog"}, {Slug: "terros"}}},
+			{Slug: "aberrus-the-shadowed-crucible", Encounters: []raiderio.Encounter{{Slug: "kazzara"}, {Slug: "terros"}}},
+		},
+	}
+
+	testCases := []struct {
+		boss         string
+		expectedRaid string
+		expectedErr  error
+	}{
+		{boss: "kazzara", expectedRaid: "aberrus-the-shadowed-crucible"},
+		{boss: "eranog", expectedRaid: "vault-of-the-incarnates"},
+		{boss: "terros", expectedRaid: "vault-of-the-incarnates"},
+		{boss: "fyrakk", expectedErr: raiderio.ErrInvalidBoss},
+	}
+
+	for _, tc := range testCases {
+		raid, enc, err := raids.RaidForBoss(tc.boss)
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if err == nil && (raid.Slug != tc.expectedRaid || enc.Slug != tc.boss) {
+			t.Fatalf("expected raid: %v boss: %v, got: %v boss: %v", tc.expectedRaid, tc.boss, raid.Slug, enc.Slug)
+		}
+	}
+}
+
+func TestComputedAvgItemLevel(t *testing.T) {
+	testCases := []struct {
+		ilvls       []int
+		expectedAvg float64
+	}{
+		{ilvls: []int{630, 635, 640}, expectedAvg: 635},
+		{ilvls: []int{630, 0, 631}, expectedAvg: 630.5},
+		{ilvls: nil, expectedAvg: 0},
+	}
+
+	for _, tc := range testCases {
+		var k raiderio.BossKill
+		for _, ilvl := range tc.ilvls {
+			k.Roster = append(k.Roster, raiderio.Character{Gear: raiderio.Gear{ItemLevelEquipped: ilvl}})
+		}
+
+		if k.ComputedAvgItemLevel() != tc.expectedAvg {
+			t.Fatalf("expected average item level: %v, got: %v", tc.expectedAvg, k.ComputedAvgItemLevel())
+		}
+	}
+}
+
+func TestRaidRankingsIsEmpty(t *testing.T) {
+	testCases := []struct {
+		body          string
+		expectedEmpty bool
+	}{
+		{body: `{"raidRankings":[]}`, expectedEmpty: true},
+		{body: `{}`, expectedEmpty: true},
+		{body: `{"raidRankings":[{"rank":1}]}`, expectedEmpty: false},
+	}
+
+	for _, tc := range testCases {
+		var rankings raiderio.RaidRankings
+		if err := json.Unmarshal([]byte(tc.body), &rankings); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if rankings.IsEmpty() != tc.expectedEmpty {
+			t.Fatalf("%v expected empty: %v, got: %v", tc.body, tc.expectedEmpty, rankings.IsEmpty())
+		}
+	}
+}
+
+func TestRosterQueries(t *testing.T) {
+	k := raiderio.BossKill{Roster: []raiderio.Character{
+		{Name: "Highervalue", Realm: "illidan", Region: "us"},
+		{Name: "Thrall", Realm: "draenor", Region: "EU"},
+		{Name: "Norealm", Region: "us"},
+		{Name: "Noregion", Realm: "illidan"},
+		{Name: "Badregion", Realm: "illidan", Region: "mars"},
+		{Name: "Worldregion", Realm: "illidan", Region: "world"},
+	}}
+
+	expected := []raiderio.CharacterQuery{
+		{Region: raiderio.Regions.US, Realm: "illidan", Name: "Highervalue"},
+		{Region: raiderio.Regions.EU, Realm: "draenor", Name: "Thrall"},
+	}
+
+	queries, skipped := k.RosterQueries()
+	if len(queries) != len(expected) {
+		t.Fatalf("expected queries: %v, got: %v", len(expected), len(queries))
+	}
+
+	expectedSkipped := "Norealm,Noregion,Badregion,Worldregion"
+	if strings.Join(skipped, ",") != expectedSkipped {
+		t.Fatalf("expected skipped: %v, got: %v", expectedSkipped, skipped)
+	}
+
+	for i, q := range queries {
+		if q.Region != expected[i].Region || q.Realm != expected[i].Realm || q.Name != expected[i].Name {
+			t.Fatalf("expected query: %+v, got: %+v", expected[i], *q)
+		}
+	}
+}
+
+func TestRaidRankingsWriteCSV(t *testing.T) {
+	r := raiderio.RaidRanking{Rank: 1, RegionalRank: 1}
+	r.Guild.Name = "Echo, the Guild"
+	r.Guild.Faction = "horde"
+	r.Guild.Realm.Name = "Tarren Mill"
+	r.Guild.Region.Slug = "eu"
+	r.EncountersDefeated = []raiderio.DefeatedEncounter{{Slug: "kazzara"}, {Slug: "amalgamation-chamber"}}
+	rankings := raiderio.RaidRankings{RaidRanking: []raiderio.RaidRanking{r}}
+
+	var sb strings.Builder
+	err := rankings.WriteCSV(&sb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "rank,region_rank,guild,realm,region,faction,bosses_defeated\n" +
+		"1,1,\"Echo, the Guild\",Tarren Mill,eu,horde,2\n"
+	if sb.String() != expected {
+		t.Fatalf("expected csv: %q, got: %q", expected, sb.String())
+	}
+}
+
+func TestRaidsSchedule(t *testing.T) {
+	body := `{"raids":[
+		{"slug":"liberation-of-undermine","starts":{"us":"2025-03-04T15:00:00Z","eu":"2025-03-05T04:00:00Z"},"ends":{"us":null,"eu":null}},
+		{"slug":"nerubar-palace","starts":{"us":"2024-09-10T15:00:00Z","eu":"2024-09-11T04:00:00Z"},"ends":{"us":"2025-02-25T15:00:00Z","eu":"2025-02-26T04:00:00Z"}},
+		{"slug":"blackrock-depths","starts":{"us":"2025-01-21T15:00:00Z"},"ends":{"us":"2025-02-11T15:00:00Z"}}
+	]}`
+	var raids raiderio.Raids
+	if err := json.Unmarshal([]byte(body), &raids); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		region         *raiderio.Region
+		expectedSlugs  []string
+		expectedActive []bool
+	}{
+		{region: raiderio.Regions.US,
+			expectedSlugs:  []string{"nerubar-palace", "blackrock-depths", "liberation-of-undermine"},
+			expectedActive: []bool{false, false, true}},
+		{region: raiderio.Regions.EU,
+			expectedSlugs:  []string{"nerubar-palace", "liberation-of-undermine"},
+			expectedActive: []bool{false, true}},
+		{region: raiderio.Regions.WORLD, expectedSlugs: []string{}, expectedActive: []bool{}},
+	}
+
+	for _, tc := range testCases {
+		windows := raids.Schedule(tc.region, now)
+		if len(windows) != len(tc.expectedSlugs) {
+			t.Fatalf("expected %d raid windows, got: %d", len(tc.expectedSlugs), len(windows))
+		}
+
+		for i, w := range windows {
+			if w.Slug != tc.expectedSlugs[i] || w.Active != tc.expectedActive[i] {
+				t.Fatalf("expected raid window: %v active: %v, got: %v active: %v",
+					tc.expectedSlugs[i], tc.expectedActive[i], w.Slug, w.Active)
+			}
+		}
+	}
+
+	windows := raids.Schedule(raiderio.Regions.US, now)
+	if !windows[2].End.IsZero() || windows[0].End.IsZero() {
+		t.Fatalf("expected only the current raid to have no end, got: %v", windows)
+	}
+}
+
+func TestLatestRaidSlug(t *testing.T) {
+	body := `{"raids":[
+		{"slug":"liberation-of-undermine","starts":{"us":"2025-03-04T15:00:00Z","eu":"2025-03-05T04:00:00Z"},"ends":{"us":null,"eu":null}},
+		{"slug":"nerubar-palace","starts":{"us":"2024-09-10T15:00:00Z","eu":"2024-09-11T04:00:00Z"},"ends":{"us":"2025-02-25T15:00:00Z","eu":"2025-02-26T04:00:00Z"}},
+		{"slug":"blackrock-depths","starts":{"us":"2025-01-21T15:00:00Z"},"ends":{"us":"2025-02-11T15:00:00Z"}}
+	]}`
+	var raids raiderio.Raids
+	if err := json.Unmarshal([]byte(body), &raids); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		region       *raiderio.Region
+		now          time.Time
+		expectedSlug string
+		expectedErr  error
+	}{
+		{region: raiderio.Regions.US, now: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), expectedSlug: "liberation-of-undermine"},
+		{region: raiderio.Regions.EU, now: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), expectedSlug: "nerubar-palace"},
+		{region: raiderio.Regions.US, now: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), expectedSlug: "blackrock-depths"},
+		{region: raiderio.Regions.US, now: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), expectedErr: raiderio.ErrInvalidRaid},
+		{region: raiderio.Regions.WORLD, now: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), expectedErr: raiderio.ErrInvalidRaid},
+	}
+
+	for _, tc := range testCases {
+		slug, err := raids.LatestRaidSlug(tc.region, tc.now)
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if slug != tc.expectedSlug {
+			t.Fatalf("expected latest raid in %v: %v, got: %v", tc.region.Slug, tc.expectedSlug, slug)
+		}
+	}
+}
+
+func TestGetRaidRankingsAllDifficulties(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("difficulty") {
+		case "mythic":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode":400,"error":"Bad Request","message":"Failed to find raid"}`))
+		default:
+			w.Write([]byte(`{"raidRankings":[{"rank":1,"guild":{"name":"` + r.URL.Query().Get("difficulty") + `"}}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	rankings, errs := client.GetRaidRankingsAllDifficulties(defaultCtx, "nerubar-palace", raiderio.Regions.US, 10)
+	if len(rankings) != 2 || len(errs) != 1 {
+		t.Fatalf("expected rankings: %v errors: %v, got: %v errors: %v", 2, 1, len(rankings), len(errs))
+	}
+
+	for _, d := range []raiderio.RaidDifficulty{raiderio.Difficulty.NormalRaid, raiderio.Difficulty.HeroicRaid} {
+		if rankings[d].RaidRanking[0].Guild.Name != string(d) {
+			t.Fatalf("expected %v rankings, got: %v", d, rankings[d].RaidRanking[0].Guild.Name)
+		}
+	}
+
+	if errs[raiderio.Difficulty.MythicRaid] != raiderio.ErrInvalidRaid {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrInvalidRaid, errs[raiderio.Difficulty.MythicRaid])
+	}
+}
+
+func TestDaysSinceKill(t *testing.T) {
+	defeatedAt := time.Date(2024, 9, 20, 23, 30, 0, 0, time.UTC)
+	testCases := []struct {
+		defeatedAt   time.Time
+		now          time.Time
+		expectedDays int
+	}{
+		{defeatedAt: defeatedAt, now: defeatedAt.Add(42*24*time.Hour + time.Hour), expectedDays: 42},
+		{defeatedAt: defeatedAt, now: time.Date(2024, 9, 21, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60)), expectedDays: 0},
+		{defeatedAt: defeatedAt, now: time.Date(2024, 9, 21, 20, 0, 0, 0, time.FixedZone("PDT", -7*60*60)), expectedDays: 1},
+		{defeatedAt: defeatedAt, now: defeatedAt.Add(-time.Hour), expectedDays: 0},
+		{defeatedAt: time.Time{}, now: defeatedAt, expectedDays: -1},
+	}
+
+	for _, tc := range testCases {
+		kill := raiderio.BossKillData{DefeatedAt: tc.defeatedAt}
+		if days := kill.DaysSinceKill(tc.now); days != tc.expectedDays {
+			t.Fatalf("expected days since kill at %v: %v, got: %v", tc.now, tc.expectedDays, days)
+		}
+	}
+}
+
+func TestGetRaidRankingsForRealms(t *testing.T) {
+	responses := map[string]string{
+		"area-52": `{"raidRankings":[{"rank":30,"guild":{"id":3,"name":"Ascend"}},{"rank":4,"region_rank":3,"guild":{"id":1,"name":"Echo"}}]}`,
+		"illidan": `{"raidRankings":[{"rank":1,"guild":{"id":2,"name":"Liquid"}},{"rank":4,"region_rank":2,"guild":{"id":1,"name":"Echo"}}]}`,
+		"ragnaros": `{"raidRankings":[{"rank":12,"guild":{"name":"Warpath","realm":{"slug":"ragnaros"},"region":{"slug":"us"}}},
+			{"rank":12,"guild":{"name":"warpath","realm":{"slug":"ragnaros"},"region":{"slug":"us"}}}]}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		realm := r.URL.Query().Get("realm")
+		// the first realm responds last, duplicates must still resolve to it
+		if realm == "illidan" {
+			time.Sleep(time.Millisecond * 20)
+		}
+
+		body, ok := responses[realm]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode":400,"error":"Bad Request","message":"Failed to find realm"}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	rankings, errs := client.GetRaidRankingsForRealms(defaultCtx, &raiderio.RaidQuery{
+		Slug:       "nerubar-palace",
+		Difficulty: raiderio.Difficulty.MythicRaid,
+		Region:     raiderio.Regions.US,
+	}, []string{"illidan", "area-52", "ragnaros", "missing"})
+
+	expected := []string{"Liquid", "Echo", "Warpath", "Ascend"}
+	var names []string
+	for _, r := range rankings.RaidRanking {
+		names = append(names, r.Guild.Name)
+	}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected guilds: %v, got: %v", expected, names)
+	}
+
+	if rankings.RaidRanking[1].RegionalRank != 2 {
+		t.Fatalf("expected duplicate guild from the first realm, got regional rank: %v", rankings.RaidRanking[1].RegionalRank)
+	}
+
+	if len(errs) != 1 || errs["missing"] != raiderio.ErrInvalidRealm {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrInvalidRealm, errs)
+	}
+}
+
+func TestGuildRaidTimeline(t *testing.T) {
+	var staticRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/raiding/static-data") {
+			staticRequests++
+			if r.URL.Query().Get("expansion_id") != "9" {
+				w.Write([]byte(`{"raids":[]}`))
+				return
+			}
+			w.Write([]byte(`{"raids":[{"slug":"vault-of-the-incarnates","encounters":[
+				{"slug":"eranog"},{"slug":"terros"},{"slug":"the-primal-council"},{"slug":"raszageth"}]}]}`))
+			return
+		}
+
+		switch r.URL.Query().Get("boss") {
+		case "eranog":
+			w.Write([]byte(`{"kill":{"defeatedAt":"2022-12-21T00:05:00.000Z","isSuccess":true},"roster":[]}`))
+		case "terros":
+			w.Write([]byte(`{"kill":{"defeatedAt":"2022-12-20T21:30:00.000Z","isSuccess":true},"roster":[]}`))
+		case "the-primal-council":
+			w.Write([]byte(`{"kill":null,"roster":[]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode":400,"error":"Bad Request","message":"Failed to find boss kill"}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	timeline, err := client.GuildRaidTimeline(defaultCtx, raiderio.Regions.US, "illidan", "liquid",
+		"vault-of-the-incarnates", raiderio.Difficulty.MythicRaid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(timeline) != 2 || timeline[0].Kill.DefeatedAt.After(timeline[1].Kill.DefeatedAt) {
+		t.Fatalf("expected two kills in kill order, got: %+v", timeline)
+	}
+
+	if staticRequests != 2 {
+		t.Fatalf("expected static data requests: %v, got: %v", 2, staticRequests)
+	}
+
+	_, err = client.GuildRaidTimeline(defaultCtx, raiderio.Regions.US, "illidan", "liquid",
+		"nerubar-palace", raiderio.Difficulty.MythicRaid)
+	if err != raiderio.ErrInvalidRaid {
+		t.Fatalf("expected error: %v, got: %v", raiderio.ErrInvalidRaid, err)
+	}
+}
@@ -0,0 +1,88 @@
+package raiderio
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitInfo reports the api request quota from the most recent response
+// Known is false when the api did not send rate limit headers
+type RateLimitInfo struct {
+	Known     bool
+	Limit     int
+	Remaining int
+}
+
+// parseRateLimit reads the X-RateLimit-Limit and X-RateLimit-Remaining
+// response headers. Both headers must be present and numeric to be known
+func parseRateLimit(h http.Header) RateLimitInfo {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return RateLimitInfo{}
+	}
+
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimitInfo{}
+	}
+
+	return RateLimitInfo{Known: true, Limit: limit, Remaining: remaining}
+}
+
+// LastRateLimit returns the rate limit reported by the most recent api response
+// When the client is shared between goroutines, the value returned may
+// belong to a request made by another goroutine
+func (c *Client) LastRateLimit() RateLimitInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastRateLimit
+}
+
+// RateLimitError is returned when the api rejects a request for exceeding
+// the rate limit. errors.Is(err, ErrRateLimited) reports true for it
+// Use errors.As to read how long the api asked to wait before retrying
+type RateLimitError struct {
+	Message    string
+	retryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Message == "" {
+		return ErrRateLimited.Error()
+	}
+	return ErrRateLimited.Error() + ": " + e.Message
+}
+
+// Is reports whether target is ErrRateLimited
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RetryAfter returns how long the api asked to wait before retrying, from
+// the Retry-After header. Returns 0 when the api did not say
+func (e *RateLimitError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// newRateLimitError creates a RateLimitError, reading the Retry-After
+// header, which is either a number of seconds or an HTTP date
+func newRateLimitError(h http.Header, message string, now time.Time) *RateLimitError {
+	e := &RateLimitError{Message: message}
+	v := h.Get("Retry-After")
+	if v == "" {
+		return e
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs > 0 {
+			e.retryAfter = time.Duration(secs) * time.Second
+		}
+		return e
+	}
+
+	if at, err := http.ParseTime(v); err == nil && at.After(now) {
+		e.retryAfter = at.Sub(now)
+	}
+	return e
+}
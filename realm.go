@@ -1,5 +1,11 @@
 package raiderio
 
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
 // Realm is a struct that represents a realm available in Raider.IO API
 type Realm struct {
 	Id               int    `json:"id"`
@@ -11,3 +17,109 @@ type Realm struct {
 	Locale           string `json:"locale"`
 	IsConnected      bool   `json:"isConnected"`
 }
+
+// realmDisplayName turns a realm slug into a display name, ex:
+// "argent-dawn" becomes "Argent Dawn"
+// The library has no realm static data, so the display name is a best
+// effort. Values which are not lowercase are assumed to already be a
+// display name and are returned unchanged
+func realmDisplayName(slug string) string {
+	if slug != strings.ToLower(slug) {
+		return slug
+	}
+
+	words := strings.FieldsFunc(slug, func(r rune) bool {
+		return r == '-' || r == ' '
+	})
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// euRealmSlugs maps EU realm names whose slug cannot be derived from the
+// name, mostly the Russian realms, which use English slugs
+// Keys are lowercased realm names
+var euRealmSlugs = map[string]string{
+	"азурегос":         "azuregos",
+	"борейская тундра": "borean-tundra",
+	"вечная песня":     "eversong",
+	"галакронд":        "galakrond",
+	"гордунни":         "gordunni",
+	"гром":             "grom",
+	"дракономор":       "fordragon",
+	"король-лич":       "lich-king",
+	"пиратская бухта":  "booty-bay",
+	"подземье":         "deepholm",
+	"разувий":          "razuvious",
+	"ревущий фьорд":    "howling-fjord",
+	"свежеватель душ":  "soulflayer",
+	"седогрив":         "greymane",
+	"страж смерти":     "deathguard",
+	"термоштепсель":    "thermaplugg",
+	"ткач смерти":      "deathweaver",
+	"черный шрам":      "blackscar",
+	"ясеневый лес":     "ashenvale",
+}
+
+// realmLetterFolds maps the accented letters used in EU realm names to
+// the letters the realm slugs use in their place
+var realmLetterFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a",
+	'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y",
+	'ß': "ss",
+}
+
+// NormalizeRealm converts a realm display name into the realm slug used by
+// the Raider.IO API, ex: "Conseil des Ombres" becomes "conseil-des-ombres"
+// and "Pozzo dell'Eternità" becomes "pozzo-delleternita"
+// Accented letters are folded and punctuation is removed, see Slugify
+// EU realms with non latin names are looked up in a table of known realms
+// Values which are already a slug are returned unchanged
+func NormalizeRealm(region *Region, name string) string {
+	name = strings.TrimSpace(name)
+	if isRealmSlug(name) {
+		return name
+	}
+
+	lower := strings.ToLower(name)
+	if region != nil && region.Slug == Regions.EU.Slug {
+		if slug, ok := euRealmSlugs[lower]; ok {
+			return slug
+		}
+	}
+
+	var sb strings.Builder
+	for _, r := range lower {
+		if f, ok := realmLetterFolds[r]; ok {
+			sb.WriteString(f)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return Slugify(sb.String())
+}
+
+// isRealmSlug reports whether a realm only contains the lowercase letters,
+// digits and hyphens a realm slug is made of
+func isRealmSlug(realm string) bool {
+	if realm == "" {
+		return false
+	}
+
+	for _, r := range realm {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package raiderio_test
+
+import (
+	"testing"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestNormalizeRealm(t *testing.T) {
+	testCases := []struct {
+		region       *raiderio.Region
+		name         string
+		expectedSlug string
+	}{
+		{region: raiderio.Regions.EU, name: "Conseil des Ombres", expectedSlug: "conseil-des-ombres"},
+		{region: raiderio.Regions.EU, name: "Die Silberne Hand", expectedSlug: "die-silberne-hand"},
+		{region: raiderio.Regions.EU, name: "La Croisade écarlate", expectedSlug: "la-croisade-ecarlate"},
+		{region: raiderio.Regions.EU, name: "Pozzo dell'Eternità", expectedSlug: "pozzo-delleternita"},
+		{region: raiderio.Regions.EU, name: "Aggra (Português)", expectedSlug: "aggra-portugues"},
+		{region: raiderio.Regions.EU, name: "Festung der Stürme", expectedSlug: "festung-der-sturme"},
+		{region: raiderio.Regions.EU, name: "Ревущий фьорд", expectedSlug: "howling-fjord"},
+		{region: raiderio.Regions.EU, name: "Король-лич", expectedSlug: "lich-king"},
+		{region: raiderio.Regions.EU, name: "howling-fjord", expectedSlug: "howling-fjord"},
+		{region: raiderio.Regions.US, name: "Mal'Ganis", expectedSlug: "malganis"},
+		{region: raiderio.Regions.US, name: "Azjol-Nerub", expectedSlug: "azjolnerub"},
+		{region: raiderio.Regions.US, name: "Argent Dawn", expectedSlug: "argent-dawn"},
+		{region: nil, name: " Illidan ", expectedSlug: "illidan"},
+		{region: raiderio.Regions.EU, name: "", expectedSlug: ""},
+	}
+
+	for _, tc := range testCases {
+		slug := raiderio.NormalizeRealm(tc.region, tc.name)
+		if slug != tc.expectedSlug {
+			t.Fatalf("%v expected slug: %v, got: %v", tc.name, tc.expectedSlug, slug)
+		}
+	}
+}
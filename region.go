@@ -1,5 +1,7 @@
 package raiderio
 
+import "strings"
+
 // Region is a struct that represents a region available in Raider.IO API
 type Region struct {
 	Name      string `json:"name"`
@@ -47,3 +49,21 @@ var Regions = struct {
 		ShortName: "cn",
 	},
 }
+
+// RegionBySlug returns the region with the given slug, ex: "us"
+// Returns nil if the slug is not a known region
+func RegionBySlug(slug string) *Region {
+	slug = strings.ToLower(slug)
+	for _, r := range []*Region{Regions.WORLD, Regions.US, Regions.EU, Regions.KR, Regions.TW, Regions.CN} {
+		if slug == r.Slug {
+			return r
+		}
+	}
+	return nil
+}
+
+// realmLooksLikeRegion reports whether a realm is actually a region slug
+// ex: "us", which usually means the realm and region were swapped
+func realmLooksLikeRegion(realm string) bool {
+	return RegionBySlug(realm) != nil
+}
@@ -1,11 +1,16 @@
 package raiderio
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
 type apiErrorResponse struct {
@@ -21,35 +26,245 @@ type apiErrorResponse struct {
 // so in cases where the realm or the character name cannot be found, developer is presented
 // with that error state.
 func (c *Client) getAPIResponse(ctx context.Context, reqUrl string) ([]byte, error) {
+	// cached by the url actually sent, so clients sharing a cache with
+	// different default params never read each other's responses
+	reqUrl = c.withDefaultParams(reqUrl)
+
+	var etag string
+	var cached []byte
+	if c.etagCache != nil {
+		etag, cached, _ = c.etagCache.Get(reqUrl)
+	}
+
+	// without a cached body a 304 cannot be served, so request the full body
+	if cached == nil {
+		etag = ""
+	}
+
+	resp, err := c.doAPIRequest(ctx, reqUrl, etag)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body []byte
+	if resp.StatusCode == http.StatusNotModified {
+		body = cached
+	} else {
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, wrapReadError(err)
+		}
+
+		if c.etagCache != nil && resp.Header.Get("ETag") != "" {
+			c.etagCache.Set(reqUrl, resp.Header.Get("ETag"), body)
+		}
+	}
+
+	if c.rawResponses {
+		c.mu.Lock()
+		c.lastRaw = body
+		c.mu.Unlock()
+	}
+
+	return body, nil
+}
+
+// doAPIRequest makes a GET request to the Raider.IO API and returns the
+// response with an unread body, which the caller must close
+// When etag is set it is sent as If-None-Match, and a 304 Not Modified
+// response is returned as is. Other non-200 responses are read, closed and
+// returned as an error
+// Default params already set on reqUrl are not appended again
+func (c *Client) doAPIRequest(ctx context.Context, reqUrl string, etag string) (*http.Response, error) {
+	reqUrl = c.withDefaultParams(reqUrl)
+
+	cancel := context.CancelFunc(func() {})
+	if c.attemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.attemptTimeout)
+	}
+
+	resp, err := c.sendAPIRequest(ctx, reqUrl, etag)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// the attempt's deadline covers reading the body, so it is only
+	// released once the caller closes the body
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// withDefaultParams appends the client's default query params to reqUrl,
+// skipping params reqUrl already sets, so endpoint params always win
+func (c *Client) withDefaultParams(reqUrl string) string {
+	if len(c.defaultParams) == 0 {
+		return reqUrl
+	}
+
+	u, err := url.Parse(reqUrl)
+	if err != nil {
+		return reqUrl
+	}
+
+	q := u.Query()
+	extra := url.Values{}
+	for k, vs := range c.defaultParams {
+		if _, ok := q[k]; !ok {
+			extra[k] = vs
+		}
+	}
+	if len(extra) == 0 {
+		return reqUrl
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery += "&"
+	}
+	u.RawQuery += extra.Encode()
+	return u.String()
+}
+
+// cancelOnClose releases a request's context when its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// sendAPIRequest sends a single GET request for doAPIRequest
+func (c *Client) sendAPIRequest(ctx context.Context, reqUrl string, etag string) (*http.Response, error) {
+	var requestID string
+	if c.requestID != nil {
+		requestID = c.requestID(ctx)
+	}
+
+	if c.connTrace != nil {
+		var report func() ConnTrace
+		ctx, report = withConnTrace(ctx, ConnTrace{Url: reqUrl, RequestID: requestID}, time.Now())
+		defer func() { c.connTrace(report()) }()
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, nil)
 	if err != nil {
 		return nil, errors.New("error creating HTTP request")
 	}
 
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if requestID != "" {
+		req.Header.Set(requestIDHeader, requestID)
+	}
+
 	resp, err := c.HttpClient.Do(req)
 	if err != nil {
 		return nil, wrapHttpError(err)
 	}
 
-	var body []byte
-	body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.New("error reading response body")
+	c.mu.Lock()
+	c.lastRateLimit = parseRateLimit(resp.Header)
+	c.mu.Unlock()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return resp, nil
+	}
+
+	// only reached when the redirect policy returned http.ErrUseLastResponse
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.StatusCode != http.StatusNotModified {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrRedirectNotFollowed, resp.Header.Get("Location"))
 	}
 
 	// If not 200, api is returning an error state
 	if resp.StatusCode != 200 {
-		var responseBody apiErrorResponse
-		err = json.Unmarshal(body, &responseBody)
-		// unmarshal error implies response is in an incorrect format
-		// instead of api message, return http status
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, wrapApiError(&responseBody)
+			return nil, wrapReadError(err)
 		}
 
-		// return error with message directly from the api
+		// an unmarshal error implies the response is in an incorrect format,
+		// the error is then mapped from the http status alone
+		var responseBody apiErrorResponse
+		_ = json.Unmarshal(body, &responseBody)
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, newRateLimitError(resp.Header, responseBody.Message, c.clock())
+		}
+
+		// the http status takes precedence over the status in the body,
+		// which the api does not always include
+		responseBody.StatusCode = resp.StatusCode
 		return nil, wrapApiError(&responseBody)
 	}
 
-	return body, nil
+	return resp, nil
+}
+
+// unmarshalJSON decodes an api response body into v
+// Lenient decoding returns an error with errMsg when the body cannot be
+// mapped to v. Strict decoding also rejects fields v does not model, and
+// returns ErrDecode wrapping the decoder's error
+func unmarshalJSON(body []byte, v interface{}, strict bool, errMsg string) error {
+	if !strict {
+		err := json.Unmarshal(body, v)
+		if err != nil {
+			return errors.New(errMsg)
+		}
+		return nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.DisallowUnknownFields()
+	err := d.Decode(v)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	return nil
+}
+
+// emptyJSONArray reports whether b is an empty json array, which the api
+// occasionally returns in place of an empty object, ex: a guild with no
+// raid progression
+func emptyJSONArray(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || b[0] != '[' {
+		return false
+	}
+
+	var a []json.RawMessage
+	return json.Unmarshal(b, &a) == nil && len(a) == 0
+}
+
+// validateRawFields rejects user supplied fields the api is known not to
+// support. raid_progression cannot be scoped, ex: "raid_progression:10",
+// the api returns the progression of every raid instead
+func validateRawFields(raw []string) error {
+	for _, f := range raw {
+		if strings.HasPrefix(strings.TrimSpace(f), "raid_progression:") {
+			return ErrScopedRaidProgression
+		}
+	}
+	return nil
+}
+
+// appendRawFields appends user supplied fields to the requested fields
+// Fields are kept as is and in order, so colon scoped fields such as
+// "mythic_plus_scores_by_season:current" keep their argument
+// Empty fields are dropped
+func appendRawFields(fields []string, raw []string) []string {
+	for _, f := range raw {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
 }
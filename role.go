@@ -0,0 +1,83 @@
+package raiderio
+
+import "strings"
+
+// Role is a string type that represents the role a character plays
+// Options are "tank", "healer", "dps" and "unknown"
+type Role string
+
+// Options for the roles a character can play
+var Roles = struct {
+	Tank    Role
+	Healer  Role
+	DPS     Role
+	Unknown Role
+}{
+	Tank:    "tank",
+	Healer:  "healer",
+	DPS:     "dps",
+	Unknown: "unknown",
+}
+
+// String returns the display name of the role
+func (r Role) String() string {
+	switch r {
+	case Roles.Tank:
+		return "Tank"
+	case Roles.Healer:
+		return "Healer"
+	case Roles.DPS:
+		return "DPS"
+	}
+	return "Unknown"
+}
+
+// specRoles maps class slugs to each of the class's spec slugs and
+// the role that spec plays
+var specRoles = map[string]map[string]Role{
+	"death-knight": {"blood": Roles.Tank, "frost": Roles.DPS, "unholy": Roles.DPS},
+	"demon-hunter": {"havoc": Roles.DPS, "vengeance": Roles.Tank},
+	"druid":        {"balance": Roles.DPS, "feral": Roles.DPS, "guardian": Roles.Tank, "restoration": Roles.Healer},
+	"evoker":       {"augmentation": Roles.DPS, "devastation": Roles.DPS, "preservation": Roles.Healer},
+	"hunter":       {"beast-mastery": Roles.DPS, "marksmanship": Roles.DPS, "survival": Roles.DPS},
+	"mage":         {"arcane": Roles.DPS, "fire": Roles.DPS, "frost": Roles.DPS},
+	"monk":         {"brewmaster": Roles.Tank, "mistweaver": Roles.Healer, "windwalker": Roles.DPS},
+	"paladin":      {"holy": Roles.Healer, "protection": Roles.Tank, "retribution": Roles.DPS},
+	"priest":       {"discipline": Roles.Healer, "holy": Roles.Healer, "shadow": Roles.DPS},
+	"rogue":        {"assassination": Roles.DPS, "outlaw": Roles.DPS, "subtlety": Roles.DPS},
+	"shaman":       {"elemental": Roles.DPS, "enhancement": Roles.DPS, "restoration": Roles.Healer},
+	"warlock":      {"affliction": Roles.DPS, "demonology": Roles.DPS, "destruction": Roles.DPS},
+	"warrior":      {"arms": Roles.DPS, "fury": Roles.DPS, "protection": Roles.Tank},
+}
+
+// GuessRole returns the role the character plays
+// The role reported by the api is used when present, otherwise the role
+// is looked up from the character's class and spec
+// Returns Roles.Unknown when the class and spec are not recognized
+func (c *Character) GuessRole() Role {
+	switch strings.ToLower(c.ActiveRole) {
+	case "tank":
+		return Roles.Tank
+	case "healer", "healing":
+		return Roles.Healer
+	case "dps":
+		return Roles.DPS
+	}
+
+	spec := c.ActiveSpec
+	if spec == "" {
+		spec = c.Spec
+	}
+
+	role, ok := specRoles[toSlug(c.Class)][toSlug(spec)]
+	if !ok {
+		return Roles.Unknown
+	}
+	return role
+}
+
+// toSlug lowercases a display name and replaces spaces with hyphens
+// ex: "Death Knight" becomes "death-knight"
+func toSlug(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "-")
+}
@@ -0,0 +1,111 @@
+package raiderio
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// warnSchemaDrift reports the fields of an api response body which v does
+// not model to the WithSchemaWarnings callback, if one is set
+// Called after body was successfully decoded into v
+func (c *Client) warnSchemaDrift(endpoint string, body []byte, v interface{}) {
+	if c.schemaWarnings == nil {
+		return
+	}
+
+	fields := unknownFields(body, reflect.TypeOf(v))
+	if len(fields) != 0 {
+		c.schemaWarnings(endpoint, fields)
+	}
+}
+
+// unknownFields returns the sorted, deduplicated paths of the fields in body
+// which type t does not model, ex: "gear.items.head.new_field"
+// Array elements share their array's path
+func unknownFields(body []byte, t reflect.Type) []string {
+	var raw interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	collectUnknownFields(raw, t, "", seen)
+
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func collectUnknownFields(raw interface{}, t reflect.Type, path string, seen map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Structs with their own UnmarshalJSON decide which keys they accept
+	if t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return
+	}
+
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Map:
+			for _, e := range v {
+				collectUnknownFields(e, t.Elem(), path, seen)
+			}
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for k, e := range v {
+				name := k
+				if path != "" {
+					name = path + "." + k
+				}
+
+				// encoding/json matches keys to fields case insensitively
+				ft, ok := fields[strings.ToLower(k)]
+				if !ok {
+					seen[name] = true
+					continue
+				}
+				collectUnknownFields(e, ft, name, seen)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, e := range v {
+				collectUnknownFields(e, t.Elem(), path, seen)
+			}
+		}
+	}
+}
+
+// jsonFields returns the types of the fields of struct t, keyed by their
+// lowercased json name
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for k, ft := range jsonFields(f.Type) {
+				fields[k] = ft
+			}
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	return fields
+}
@@ -0,0 +1,57 @@
+package raiderio
+
+// Season slugs the api accepts in place of a specific season
+const (
+	CurrentSeasonSlug  string = "current"
+	PreviousSeasonSlug string = "previous"
+)
+
+// seasons lists the known mythic plus season slugs for each expansion,
+// oldest first. New seasons must be appended here as they are released
+var seasons = map[Expansion][]string{
+	Expansions.BattleForAzeroth: {"season-bfa-1", "season-bfa-2", "season-bfa-3", "season-bfa-4"},
+	Expansions.Shadowlands:      {"season-sl-1", "season-sl-2", "season-sl-3", "season-sl-4"},
+	Expansions.Dragonflight:     {"season-df-1", "season-df-2", "season-df-3", "season-df-4"},
+	Expansions.WarWithin:        {"season-tww-1", "season-tww-2", "season-tww-3"},
+}
+
+// Seasons returns the known mythic plus season slugs for an expansion,
+// oldest first. Returns nil for expansions with no known seasons
+func Seasons(e Expansion) []string {
+	s := seasons[e]
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
+
+// CurrentSeason returns the latest known mythic plus season slug for an
+// expansion, or an empty string for expansions with no known seasons
+// The known seasons are pinned to this version of the library, use
+// GetMythicPlusStaticData to resolve the live current season from the api
+func CurrentSeason(e Expansion) string {
+	s := seasons[e]
+	if len(s) == 0 {
+		return ""
+	}
+	return s[len(s)-1]
+}
+
+// seasonValid reports whether the season slug is a known season,
+// or one of the "current" and "previous" aliases
+// Validates before sending to the api, since an unknown season
+// results in an empty result instead of an error message
+func seasonValid(season string) bool {
+	if season == CurrentSeasonSlug || season == PreviousSeasonSlug {
+		return true
+	}
+
+	for _, s := range seasons {
+		for _, slug := range s {
+			if slug == season {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package raiderio_test
+
+import (
+	"testing"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestCurrentSeason(t *testing.T) {
+	testCases := []struct {
+		expansion      raiderio.Expansion
+		expectedSeason string
+	}{
+		{expansion: raiderio.Expansions.Dragonflight, expectedSeason: "season-df-4"},
+		{expansion: raiderio.Expansions.Shadowlands, expectedSeason: "season-sl-4"},
+		{expansion: 2, expectedSeason: ""},
+	}
+
+	for _, tc := range testCases {
+		season := raiderio.CurrentSeason(tc.expansion)
+		if season != tc.expectedSeason {
+			t.Fatalf("expected season: %v, got: %v", tc.expectedSeason, season)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package raiderio
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify converts a raid or boss display name into the slug used by
+// the Raider.IO API, ex: "Nerub-ar Palace" becomes "nerubar-palace"
+// Letters and digits are lowercased, whitespace separates words and all
+// other punctuation (apostrophes, commas, hyphens) is removed
+func Slugify(name string) string {
+	words := strings.FieldsFunc(name, unicode.IsSpace)
+
+	var slugWords []string
+	for _, w := range words {
+		var sb strings.Builder
+		for _, r := range w {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				sb.WriteRune(unicode.ToLower(r))
+			}
+		}
+
+		if sb.Len() > 0 {
+			slugWords = append(slugWords, sb.String())
+		}
+	}
+
+	return strings.Join(slugWords, "-")
+}
@@ -0,0 +1,31 @@
+package raiderio_test
+
+import (
+	"testing"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestSlugify(t *testing.T) {
+	testCases := []struct {
+		name         string
+		expectedSlug string
+	}{
+		{name: "Nerub-ar Palace", expectedSlug: "nerubar-palace"},
+		{name: "Vault of the Incarnates", expectedSlug: "vault-of-the-incarnates"},
+		{name: "Aberrus, the Shadowed Crucible", expectedSlug: "aberrus-the-shadowed-crucible"},
+		{name: "Amirdrassil, the Dream's Hope", expectedSlug: "amirdrassil-the-dreams-hope"},
+		{name: "Rasha'nan", expectedSlug: "rashanan"},
+		{name: "Nexus-Princess Ky'veza", expectedSlug: "nexusprincess-kyveza"},
+		{name: "Mug'Zee, Heads of Security", expectedSlug: "mugzee-heads-of-security"},
+		{name: "  The Primal  Council ", expectedSlug: "the-primal-council"},
+		{name: "", expectedSlug: ""},
+	}
+
+	for _, tc := range testCases {
+		slug := raiderio.Slugify(tc.name)
+		if slug != tc.expectedSlug {
+			t.Fatalf("%v expected slug: %v, got: %v", tc.name, tc.expectedSlug, slug)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package raiderio
+
+import (
+	"context"
+	"sync"
+)
+
+// staticData holds static api data preloaded onto a Client, used to
+// validate queries locally without a round trip to the api
+type staticData struct {
+	mu         sync.RWMutex
+	raids      map[Expansion]*Raids
+	mythicPlus map[Expansion]*MythicPlusStaticData
+}
+
+// PreloadStaticData fetches the static raid and mythic plus data for an
+// expansion and stores it on the client. Calling it again for the same
+// expansion refreshes the data
+// Once any static data is loaded, raid slugs in queries, and the boss slugs
+// of boss kill queries, are validated against it before sending requests,
+// as are the dungeon slugs of mythic plus runs queries, so preload every
+// expansion you intend to query
+func (c *Client) PreloadStaticData(ctx context.Context, e Expansion) error {
+	raids, err := c.GetRaids(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	mythicPlus, err := c.getMythicPlusStaticData(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	c.static.mu.Lock()
+	defer c.static.mu.Unlock()
+	if c.static.raids == nil {
+		c.static.raids = map[Expansion]*Raids{}
+		c.static.mythicPlus = map[Expansion]*MythicPlusStaticData{}
+	}
+	c.static.raids[e] = raids
+	c.static.mythicPlus[e] = mythicPlus
+	return nil
+}
+
+// StaticRaids returns the preloaded static raid data for an expansion
+// Returns false if the expansion has not been preloaded
+func (c *Client) StaticRaids(e Expansion) (*Raids, bool) {
+	c.static.mu.RLock()
+	defer c.static.mu.RUnlock()
+	raids, ok := c.static.raids[e]
+	return raids, ok
+}
+
+// StaticMythicPlus returns the preloaded static mythic plus data, the
+// seasons and dungeons, for an expansion
+// Returns false if the expansion has not been preloaded
+func (c *Client) StaticMythicPlus(e Expansion) (*MythicPlusStaticData, bool) {
+	c.static.mu.RLock()
+	defer c.static.mu.RUnlock()
+	data, ok := c.static.mythicPlus[e]
+	return data, ok
+}
+
+// findStaticRaid looks up a raid in the preloaded static data
+// loaded is false when no static data has been preloaded, in which
+// case the raid cannot be validated locally
+func (c *Client) findStaticRaid(slug string) (raid *Raid, loaded bool) {
+	c.static.mu.RLock()
+	defer c.static.mu.RUnlock()
+
+	if len(c.static.raids) == 0 {
+		return nil, false
+	}
+
+	for _, raids := range c.static.raids {
+		r, err := raids.GetRaidBySlug(slug)
+		if err == nil {
+			return r, true
+		}
+	}
+	return nil, true
+}
+
+// validateStaticBoss checks that a boss slug is an encounter of a raid in
+// the preloaded static data, ex: terros is not a boss of nerubar-palace
+// Always valid when no static data has been preloaded
+func (c *Client) validateStaticBoss(raidSlug, bossSlug string) error {
+	raid, loaded := c.findStaticRaid(raidSlug)
+	if !loaded {
+		return nil
+	}
+
+	if raid == nil {
+		return ErrInvalidRaid
+	}
+
+	for _, e := range raid.Encounters {
+		if e.Slug == bossSlug {
+			return nil
+		}
+	}
+	return ErrInvalidBoss
+}
+
+// validateStaticRaid checks a raid slug against the preloaded static data
+// Always valid when no static data has been preloaded
+func (c *Client) validateStaticRaid(slug string) error {
+	raid, loaded := c.findStaticRaid(slug)
+	if loaded && raid == nil {
+		return ErrInvalidRaid
+	}
+	return nil
+}
+
+// validateStaticDungeon checks a dungeon slug against the dungeons of every
+// season in the preloaded static data. "all" is always valid
+// Always valid when no static data has been preloaded
+func (c *Client) validateStaticDungeon(slug string) error {
+	c.static.mu.RLock()
+	defer c.static.mu.RUnlock()
+
+	if len(c.static.mythicPlus) == 0 || slug == "all" {
+		return nil
+	}
+
+	for _, data := range c.static.mythicPlus {
+		for _, d := range data.Dungeons {
+			if d.Slug == slug {
+				return nil
+			}
+		}
+
+		for _, s := range data.Seasons {
+			for _, d := range s.Dungeons {
+				if d.Slug == slug {
+					return nil
+				}
+			}
+		}
+	}
+	return ErrInvalidDungeon
+}
@@ -0,0 +1,182 @@
+package raiderio_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestPreloadStaticData(t *testing.T) {
+	var rankingRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/raiding/static-data") {
+			w.Write([]byte(`{"raids":[{"slug":"vault-of-the-incarnates","encounters":[{"slug":"terros"}]}]}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/mythic-plus/static-data") {
+			w.Write([]byte(`{"seasons":[],"dungeons":[]}`))
+			return
+		}
+		rankingRequests++
+		w.Write([]byte(`{"raidRankings":[]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	if _, ok := client.StaticRaids(raiderio.Expansions.Dragonflight); ok {
+		t.Fatalf("expected no static raids before preloading")
+	}
+
+	err := client.PreloadStaticData(defaultCtx, raiderio.Expansions.Dragonflight)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := client.StaticRaids(raiderio.Expansions.Dragonflight); !ok {
+		t.Fatalf("expected static raids after preloading")
+	}
+
+	testCases := []struct {
+		slug             string
+		expectedErrMsg   string
+		expectedRequests int
+	}{
+		{slug: "vault-of-the-incarnates", expectedRequests: 1},
+		{slug: "nerubar-palace", expectedErrMsg: "invalid raid", expectedRequests: 1},
+	}
+
+	for _, tc := range testCases {
+		_, err := client.GetRaidRankings(defaultCtx, &raiderio.RaidQuery{
+			Slug:       tc.slug,
+			Difficulty: raiderio.Difficulty.MythicRaid,
+			Region:     raiderio.Regions.US,
+		})
+		if err != nil && err.Error() != tc.expectedErrMsg {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErrMsg, err.Error())
+		}
+
+		if rankingRequests != tc.expectedRequests {
+			t.Fatalf("expected %d ranking requests, got: %d", tc.expectedRequests, rankingRequests)
+		}
+	}
+}
+
+func TestPreloadStaticDataBossKill(t *testing.T) {
+	var bossKillRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/raiding/static-data") {
+			w.Write([]byte(`{"raids":[{"slug":"nerubar-palace","encounters":[{"slug":"ulgrax-the-devourer"}]},
+				{"slug":"vault-of-the-incarnates","encounters":[{"slug":"terros"}]}]}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/mythic-plus/static-data") {
+			w.Write([]byte(`{"seasons":[],"dungeons":[]}`))
+			return
+		}
+		bossKillRequests++
+		w.Write([]byte(`{"kill":{},"roster":[]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+	query := raiderio.GuildBossKillQuery{
+		Region:     raiderio.Regions.US,
+		Realm:      "illidan",
+		GuildName:  "liquid",
+		Difficulty: raiderio.Difficulty.MythicRaid,
+	}
+
+	testCases := []struct {
+		preload          bool
+		raidSlug         string
+		bossSlug         string
+		expectedErr      error
+		expectedRequests int
+	}{
+		{raidSlug: "nerubar-palace", bossSlug: "terros", expectedRequests: 1},
+		{preload: true, raidSlug: "nerubar-palace", bossSlug: "ulgrax-the-devourer", expectedRequests: 2},
+		{preload: true, raidSlug: "nerubar-palace", bossSlug: "terros", expectedErr: raiderio.ErrInvalidBoss, expectedRequests: 2},
+		{preload: true, raidSlug: "amirdrassil-the-dreams-hope", bossSlug: "terros", expectedErr: raiderio.ErrInvalidRaid, expectedRequests: 2},
+	}
+
+	for _, tc := range testCases {
+		if tc.preload {
+			err := client.PreloadStaticData(defaultCtx, raiderio.Expansions.WarWithin)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		q := query
+		q.RaidSlug, q.BossSlug = tc.raidSlug, tc.bossSlug
+		_, err := client.GetGuildBossKill(defaultCtx, &q)
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if bossKillRequests != tc.expectedRequests {
+			t.Fatalf("expected %d boss kill requests, got: %d", tc.expectedRequests, bossKillRequests)
+		}
+	}
+}
+
+func TestPreloadStaticDataDungeon(t *testing.T) {
+	var runsRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/raiding/static-data") {
+			w.Write([]byte(`{"raids":[]}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/mythic-plus/static-data") {
+			w.Write([]byte(`{"seasons":[{"slug":"season-tww-3","dungeons":[{"slug":"ecodome-aldani"}]}],
+				"dungeons":[{"slug":"the-stonevault"}]}`))
+			return
+		}
+		runsRequests++
+		w.Write([]byte(`{"rankings":[]}`))
+	}))
+	defer ts.Close()
+
+	client := raiderio.NewClient()
+	client.ApiUrl = ts.URL
+
+	err := client.PreloadStaticData(defaultCtx, raiderio.Expansions.WarWithin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := client.StaticMythicPlus(raiderio.Expansions.WarWithin); !ok {
+		t.Fatalf("expected static mythic plus data after preloading")
+	}
+
+	testCases := []struct {
+		dungeon          string
+		expectedErr      error
+		expectedRequests int
+	}{
+		{dungeon: "all", expectedRequests: 1},
+		{dungeon: "ecodome-aldani", expectedRequests: 2},
+		{dungeon: "the-stonevault", expectedRequests: 3},
+		{dungeon: "halls-of-valor", expectedErr: raiderio.ErrInvalidDungeon, expectedRequests: 3},
+	}
+
+	for _, tc := range testCases {
+		_, err := client.GetMythicPlusRuns(defaultCtx, &raiderio.MythicPlusRunsQuery{
+			Region:  raiderio.Regions.US,
+			Dungeon: tc.dungeon,
+		})
+		if err != tc.expectedErr {
+			t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+		}
+
+		if runsRequests != tc.expectedRequests {
+			t.Fatalf("expected %d runs requests, got: %d", tc.expectedRequests, runsRequests)
+		}
+	}
+}
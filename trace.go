@@ -0,0 +1,98 @@
+package raiderio
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ConnTrace reports connection timings for a single api request
+// Durations are zero for phases which did not happen, ex: DNS and
+// Connect are zero when an idle connection was reused
+// RequestID is set when the client is created WithRequestIDFromContext
+type ConnTrace struct {
+	Url          string
+	RequestID    string
+	Reused       bool
+	WasIdle      bool
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration
+}
+
+// connTracer records the timings of a ConnTrace from httptrace callbacks
+// The callbacks may run concurrently, ex: the dials to each address of a
+// host raced by the transport (Happy Eyeballs), so all state is guarded
+// by mu and connect timings are keyed by address
+type connTracer struct {
+	mu           sync.Mutex
+	ct           ConnTrace
+	start        time.Time
+	dnsStart     time.Time
+	connectStart map[string]time.Time
+	tlsStart     time.Time
+}
+
+// withConnTrace attaches an httptrace.ClientTrace to the context which
+// records timings for the request. FirstByte is measured from start
+// The returned func reports the timings recorded so far
+func withConnTrace(ctx context.Context, ct ConnTrace, start time.Time) (context.Context, func() ConnTrace) {
+	t := &connTracer{ct: ct, start: start, connectStart: map[string]time.Time{}}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.ct.Reused = info.Reused
+			t.ct.WasIdle = info.WasIdle
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.ct.DNS = time.Since(t.dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connectStart[network+"/"+addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// only the first successful dial is timed, the others lost the race
+			connectStart, ok := t.connectStart[network+"/"+addr]
+			if err == nil && ok && t.ct.Connect == 0 {
+				t.ct.Connect = time.Since(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.ct.TLSHandshake = time.Since(t.tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.ct.FirstByte = time.Since(t.start)
+		},
+	}
+
+	report := func() ConnTrace {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return t.ct
+	}
+	return httptrace.WithClientTrace(ctx, trace), report
+}
@@ -0,0 +1,72 @@
+package raiderio
+
+import "sort"
+
+// VaultRunThresholds are the number of mythic plus runs needed
+// in a week to unlock each Great Vault slot
+var VaultRunThresholds = []int{1, 4, 8}
+
+// VaultItemLevels maps a keystone level to the item level of the Great Vault
+// reward it earns. Keys above the highest level earn the highest reward
+// The values are for The War Within season 2, and must be updated each season
+var VaultItemLevels = map[int]int{
+	2:  649,
+	3:  649,
+	4:  652,
+	5:  652,
+	6:  655,
+	7:  658,
+	8:  658,
+	9:  658,
+	10: 662,
+}
+
+// VaultReward is a struct that represents a single mythic plus
+// Great Vault slot. KeyLevel and ItemLevel are zero when the slot is locked
+type VaultReward struct {
+	Slot         int
+	RunsRequired int
+	Unlocked     bool
+	KeyLevel     int
+	ItemLevel    int
+}
+
+// VaultSlots computes the mythic plus Great Vault slots from the character's
+// weekly highest level runs. Each slot is rewarded at the level of the
+// nth highest key, where n is the number of runs required for the slot
+// Requires the character to be requested with MythicPlusWeeklyHighestRuns
+func (c *Character) VaultSlots() []VaultReward {
+	levels := make([]int, 0, len(c.MythicPlusWeeklyHighestRuns))
+	for _, r := range c.MythicPlusWeeklyHighestRuns {
+		levels = append(levels, r.MythicLevel)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(levels)))
+
+	rewards := make([]VaultReward, 0, len(VaultRunThresholds))
+	for i, runs := range VaultRunThresholds {
+		reward := VaultReward{Slot: i + 1, RunsRequired: runs}
+		if len(levels) >= runs {
+			reward.Unlocked = true
+			reward.KeyLevel = levels[runs-1]
+			reward.ItemLevel = vaultItemLevel(reward.KeyLevel)
+		}
+		rewards = append(rewards, reward)
+	}
+	return rewards
+}
+
+// vaultItemLevel looks up the vault reward item level for a key level
+// Levels above the table use the highest reward, levels below use zero
+func vaultItemLevel(level int) int {
+	maxLevel := 0
+	for l := range VaultItemLevels {
+		if l > maxLevel {
+			maxLevel = l
+		}
+	}
+
+	if level > maxLevel {
+		level = maxLevel
+	}
+	return VaultItemLevels[level]
+}
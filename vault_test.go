@@ -0,0 +1,41 @@
+package raiderio_test
+
+import (
+	"testing"
+
+	"github.com/tmaffia/raiderio"
+)
+
+func TestVaultSlots(t *testing.T) {
+	runs := func(levels ...int) []raiderio.MythicPlusRun {
+		var r []raiderio.MythicPlusRun
+		for _, l := range levels {
+			r = append(r, raiderio.MythicPlusRun{MythicLevel: l})
+		}
+		return r
+	}
+
+	testCases := []struct {
+		runs               []raiderio.MythicPlusRun
+		expectedItemLevels []int
+	}{
+		{runs: nil, expectedItemLevels: []int{0, 0, 0}},
+		{runs: runs(7), expectedItemLevels: []int{658, 0, 0}},
+		{runs: runs(2, 12, 4, 6), expectedItemLevels: []int{662, 649, 0}},
+		{runs: runs(10, 10, 10, 10, 9, 8, 7, 6, 5), expectedItemLevels: []int{662, 662, 655}},
+	}
+
+	for _, tc := range testCases {
+		char := raiderio.Character{MythicPlusWeeklyHighestRuns: tc.runs}
+		slots := char.VaultSlots()
+		if len(slots) != 3 {
+			t.Fatalf("expected 3 vault slots, got: %d", len(slots))
+		}
+
+		for i, s := range slots {
+			if s.ItemLevel != tc.expectedItemLevels[i] || s.Unlocked != (tc.expectedItemLevels[i] != 0) {
+				t.Fatalf("slot %d expected item level: %d, got: %+v", s.Slot, tc.expectedItemLevels[i], s)
+			}
+		}
+	}
+}